@@ -1,15 +1,26 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 
 	"github.com/mattwhite/river-go/internal/ai"
+	"github.com/mattwhite/river-go/internal/chatui"
 	"github.com/mattwhite/river-go/internal/editor"
 	"github.com/mattwhite/river-go/internal/onboarding"
 	"github.com/mattwhite/river-go/internal/statsui"
+	"github.com/mattwhite/river-go/internal/streak"
 )
 
 func printHelp() {
@@ -18,13 +29,39 @@ func printHelp() {
 	fmt.Println("Usage:")
 	fmt.Println("  river              Start the journal editor")
 	fmt.Println("  river stats        View writing statistics dashboard")
+	fmt.Println("    --json           Print stats as JSON")
+	fmt.Println("    --csv            Print stats as CSV")
+	fmt.Println("    --summary        Print a one-line summary for status bars")
+	fmt.Println("  river stats export [heatmap|weekly|monthly]")
+	fmt.Println("    --svg <file>     Save the chart as an SVG image (default: stdout)")
+	fmt.Println("    --theme <name>   light or dark (default: light)")
+	fmt.Println("    --year <year>    Year for the heatmap (default: this year)")
+	fmt.Println("  river rest <date>  Spend a streak freeze on a day (YYYY-MM-DD)")
+	fmt.Println("  river search <query>  Find the paragraphs that best match a query")
+	fmt.Println("    --semantic       Match by meaning rather than by words")
+	fmt.Println("    --limit <n>      Show up to n paragraphs (default: 10)")
+	fmt.Println("    --days, --since, --until, --tag and --journal as for the AI commands")
 	fmt.Println("  river onboard      Set up AI features (API key)")
 	fmt.Println()
-	fmt.Println("AI Commands (requires API key):")
+	fmt.Println("AI Commands (requires an API key or a local model):")
 	fmt.Println("  river prompts      Generate personalized journal prompts")
 	fmt.Println("  river think        Generate categorized TODOs from recent notes")
 	fmt.Println("  river analyze      Get insights and patterns from recent notes")
 	fmt.Println("  river todo         Extract simple actionable items from notes")
+	fmt.Println("  river chat         Ask questions about your journal")
+	fmt.Println("  Options for the commands above:")
+	fmt.Println("    --model <id>       Use another model for this run")
+	fmt.Println("    --max-tokens <n>   Limit the length of the reply")
+	fmt.Println("    --temperature <t>  Set the randomness (0-1 for Anthropic)")
+	fmt.Println("    --fresh            Ask again instead of reusing a saved reply")
+	fmt.Println("    --days <n>         Read notes from the last n days")
+	fmt.Println("    --since <date>     Read notes from this day on (YYYY-MM-DD)")
+	fmt.Println("    --until <date>     Read notes up to this day (YYYY-MM-DD)")
+	fmt.Println("    --tag <tag>        Only read paragraphs tagged #tag")
+	fmt.Println("    --journal <dir>    Read notes from another directory")
+	fmt.Println("  river ai models    List the models the configured provider offers")
+	fmt.Println("  river ai templates List, show or reset the prompt templates")
+	fmt.Println("  river ai history   List, show or diff past AI runs")
 	fmt.Println()
 	fmt.Println("Other:")
 	fmt.Println("  river help         Show this help message")
@@ -32,6 +69,399 @@ func printHelp() {
 	fmt.Println("First time? Run 'river onboard' to set up AI features.")
 }
 
+func runStats(args []string) error {
+	if len(args) > 0 && args[0] == "export" {
+		return runStatsExport(args[1:])
+	}
+
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print stats as JSON")
+	asCSV := fs.Bool("csv", false, "print stats as CSV")
+	summary := fs.Bool("summary", false, "print a one-line summary")
+	fs.Parse(args)
+
+	switch {
+	case *asJSON:
+		return statsui.WriteJSON(os.Stdout)
+	case *asCSV:
+		return statsui.WriteCSV(os.Stdout)
+	case *summary:
+		return statsui.WriteSummary(os.Stdout)
+	}
+
+	p := tea.NewProgram(statsui.InitModel(), tea.WithAltScreen(), tea.WithMouseCellMotion())
+	_, err := p.Run()
+	return err
+}
+
+// runStatsExport saves a stats chart as an image. Without a chart name, one
+// is guessed from the file name, so "--svg weekly.svg" draws the weekly bars.
+func runStatsExport(args []string) error {
+	chart := ""
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		chart, args = args[0], args[1:]
+	}
+
+	fs := flag.NewFlagSet("stats export", flag.ExitOnError)
+	svgPath := fs.String("svg", "", "file to write the SVG image to")
+	theme := fs.String("theme", "light", "light or dark")
+	year := fs.Int("year", 0, "year for the heatmap")
+	fs.Parse(args)
+
+	if chart == "" {
+		chart = "heatmap"
+		name := strings.TrimSuffix(filepath.Base(*svgPath), filepath.Ext(*svgPath))
+		for _, c := range statsui.SVGCharts {
+			if strings.Contains(strings.ToLower(name), c) {
+				chart = c
+			}
+		}
+	}
+
+	if *svgPath == "" {
+		return statsui.WriteSVG(os.Stdout, chart, *theme, *year)
+	}
+
+	var buf bytes.Buffer
+	if err := statsui.WriteSVG(&buf, chart, *theme, *year); err != nil {
+		return err
+	}
+	if err := os.WriteFile(*svgPath, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	fmt.Printf("📊 Saved the %s chart to %s\n", chart, *svgPath)
+	return nil
+}
+
+// parseAIOptions reads the flags shared by the AI commands.
+func parseAIOptions(command string, args []string) ai.Options {
+	var opts ai.Options
+	fs := flag.NewFlagSet(command, flag.ExitOnError)
+	fs.StringVar(&opts.Model, "model", "", "model id to use")
+	fs.Func("max-tokens", "longest reply, in tokens", func(value string) error {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a whole number", value)
+		}
+		opts.MaxTokens = &n
+		return nil
+	})
+	fs.Func("temperature", "randomness, from 0 (focused) upward", func(value string) error {
+		t, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+		opts.Temperature = &t
+		return nil
+	})
+	fs.BoolVar(&opts.Fresh, "fresh", false, "ask again instead of reusing a saved reply")
+	fs.IntVar(&opts.Days, "days", 0, "read notes from the last N days")
+	fs.Func("since", "first day of notes to read (YYYY-MM-DD)", dateFlag(&opts.Since))
+	fs.Func("until", "last day of notes to read (YYYY-MM-DD)", dateFlag(&opts.Until))
+	fs.StringVar(&opts.Tag, "tag", "", "only read paragraphs with this #tag")
+	fs.StringVar(&opts.Journal, "journal", "", "directory of notes to read instead of ~/river/notes")
+	fs.Parse(args)
+	return opts
+}
+
+// dateFlag parses a YYYY-MM-DD flag into t.
+func dateFlag(t *time.Time) func(string) error {
+	return func(value string) error {
+		date, err := time.ParseInLocation("2006-01-02", value, time.Local)
+		if err != nil {
+			return fmt.Errorf("%q is not a date like 2025-01-31", value)
+		}
+		*t = date
+		return nil
+	}
+}
+
+// runAICommand runs one of the AI commands. Ctrl+C cancels its request
+// rather than killing River mid-reply.
+func runAICommand(command string, args []string, run func(context.Context, ai.Options) error) error {
+	opts := parseAIOptions(command, args)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	return run(ctx, opts)
+}
+
+// runChat opens a conversation about the journal. Date flags narrow the
+// entries it searches.
+func runChat(args []string) error {
+	chat, err := ai.NewChat(parseAIOptions("chat", args))
+	if err != nil {
+		return err
+	}
+	if chat.Entries() == 0 {
+		fmt.Println("📝 No notes found. Try writing some thoughts first!")
+		return nil
+	}
+
+	p := tea.NewProgram(chatui.New(chat), tea.WithAltScreen())
+	_, err = p.Run()
+	return err
+}
+
+// runSearch prints the paragraphs that best match a query, by shared words or,
+// with --semantic, by meaning.
+func runSearch(args []string) error {
+	var w ai.Window
+	fs := flag.NewFlagSet("search", flag.ExitOnError)
+	semantic := fs.Bool("semantic", false, "match by meaning rather than by words")
+	limit := fs.Int("limit", 10, "most paragraphs to show")
+	fs.IntVar(&w.Days, "days", 0, "search notes from the last N days")
+	fs.Func("since", "first day of notes to search (YYYY-MM-DD)", dateFlag(&w.Since))
+	fs.Func("until", "last day of notes to search (YYYY-MM-DD)", dateFlag(&w.Until))
+	fs.StringVar(&w.Tag, "tag", "", "only search paragraphs with this #tag")
+	fs.StringVar(&w.Journal, "journal", "", "directory of notes to search instead of ~/river/notes")
+	fs.Parse(args)
+
+	query := strings.TrimSpace(strings.Join(fs.Args(), " "))
+	if query == "" {
+		return fmt.Errorf("usage: river search [--semantic] [--limit n] <query>")
+	}
+	if *limit < 1 {
+		return fmt.Errorf("--limit must be at least 1, got %d", *limit)
+	}
+
+	var passages []ai.Passage
+	var err error
+	method := "shared words"
+	if *semantic {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		showedProgress := false
+		passages, method, err = ai.SearchSemantic(ctx, query, w, *limit, func(status string) {
+			fmt.Fprintf(os.Stderr, "\r\033[K%s", status)
+			showedProgress = true
+		})
+		if showedProgress {
+			fmt.Fprint(os.Stderr, "\r\033[K")
+		}
+	} else {
+		passages, err = ai.SearchNotes(query, w, *limit)
+	}
+	if err != nil {
+		return err
+	}
+
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	if len(passages) == 0 {
+		fmt.Printf("🔍 Nothing matched %q.\n", query)
+		fmt.Println(dim.Render("Matched by " + method + "."))
+		return nil
+	}
+
+	dateStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("39")).Bold(true)
+	for _, p := range passages {
+		fmt.Printf("%s  %s\n", dateStyle.Render(p.Date.Format("Mon, Jan 2, 2006")), dim.Render(fmt.Sprintf("%.2f", p.Score)))
+		fmt.Println(strings.TrimSpace(p.Text))
+		fmt.Println()
+	}
+	fmt.Println(dim.Render("Matched by " + method + "."))
+	return nil
+}
+
+// runAI handles the "river ai" subcommands.
+func runAI(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: river ai models | river ai templates [list|show|reset] | river ai history [list|show|diff]")
+	}
+
+	switch args[0] {
+	case "models":
+		settings := ai.LoadSettings()
+		models, err := ai.ListModels()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Models available from %s:\n", settings.Provider)
+		for _, model := range models {
+			marker := "  "
+			if model == settings.Model {
+				marker = "* "
+			}
+			fmt.Println(marker + model)
+		}
+		return nil
+	case "templates":
+		return runTemplates(args[1:])
+	case "history":
+		return runHistory(args[1:])
+	default:
+		return fmt.Errorf("unknown ai command %q, expected models, templates or history", args[0])
+	}
+}
+
+// runHistory lists, shows and compares past AI runs.
+func runHistory(args []string) error {
+	command := "list"
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
+
+	runs, err := ai.LoadHistory()
+	if err != nil {
+		return err
+	}
+	if len(runs) == 0 {
+		fmt.Println("No AI runs saved yet. Try 'river analyze' or 'river think'.")
+		return nil
+	}
+	number := make(map[string]int)
+	for i, run := range runs {
+		number[run.ID] = i + 1
+	}
+
+	switch command {
+	case "list":
+		filter := ""
+		if len(args) > 0 {
+			filter = args[0]
+		}
+		fmt.Printf("%4s  %-12s  %-8s  %-15s  %s\n", "#", "When", "Command", "Notes", "Model")
+		for _, run := range runs {
+			if filter != "" && run.Command != filter {
+				continue
+			}
+			fmt.Printf("%4d  %-12s  %-8s  %-15s  %s\n", number[run.ID], run.Created.Format("Jan 2 15:04"),
+				run.Command, formatRunRange(run), formatRunModel(run))
+		}
+		fmt.Println("\nShow one with 'river ai history show <#>', or compare it with the run before")
+		fmt.Println("with 'river ai history diff <#>'.")
+		return nil
+
+	case "show":
+		if len(args) < 1 {
+			return fmt.Errorf("usage: river ai history show <#|id>")
+		}
+		run, err := ai.FindRun(runs, args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("#%d %s • %s\n", number[run.ID], run.Command, run.Created.Format("Monday, January 2, 2006 15:04"))
+		fmt.Printf("Notes %s (%s) • %s\n\n", formatRunRange(run), run.NotesHash, formatRunModel(run))
+		fmt.Println(strings.TrimRight(run.Output, "\n"))
+		return nil
+
+	case "diff":
+		if len(args) < 1 {
+			return fmt.Errorf("usage: river ai history diff <#|id> [<#|id>]")
+		}
+		newer, err := ai.FindRun(runs, args[0])
+		if err != nil {
+			return err
+		}
+		var older ai.Run
+		if len(args) > 1 {
+			if older, err = ai.FindRun(runs, args[1]); err != nil {
+				return err
+			}
+			if older.Created.After(newer.Created) {
+				older, newer = newer, older
+			}
+		} else {
+			var ok bool
+			if older, ok = ai.PreviousRun(runs, newer); !ok {
+				return fmt.Errorf("#%d is the first %s run, so there's nothing to compare it with", number[newer.ID], newer.Command)
+			}
+		}
+
+		removed := lipgloss.NewStyle().Foreground(lipgloss.Color("203"))
+		added := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+		fmt.Println(removed.Render(fmt.Sprintf("- #%d %s %s, notes %s", number[older.ID], older.Command,
+			older.Created.Format("Jan 2 15:04"), formatRunRange(older))))
+		fmt.Println(added.Render(fmt.Sprintf("+ #%d %s %s, notes %s", number[newer.ID], newer.Command,
+			newer.Created.Format("Jan 2 15:04"), formatRunRange(newer))))
+		if older.NotesHash == newer.NotesHash {
+			fmt.Println("  (both runs were given the same notes)")
+		}
+		fmt.Println()
+		for _, line := range ai.DiffLines(older.Output, newer.Output) {
+			switch {
+			case strings.HasPrefix(line, "- "):
+				line = removed.Render(line)
+			case strings.HasPrefix(line, "+ "):
+				line = added.Render(line)
+			}
+			fmt.Println(line)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown history command %q, expected list, show or diff", command)
+	}
+}
+
+func formatRunRange(run ai.Run) string {
+	start, err1 := time.Parse("2006-01-02", run.Start)
+	end, err2 := time.Parse("2006-01-02", run.End)
+	if err1 != nil || err2 != nil {
+		return run.Start + " – " + run.End
+	}
+	return start.Format("Jan 2") + " – " + end.Format("Jan 2")
+}
+
+func formatRunModel(run ai.Run) string {
+	if run.Model == "" {
+		return run.Provider + " default model"
+	}
+	return run.Model
+}
+
+// runTemplates lists, shows and resets the AI prompt templates.
+func runTemplates(args []string) error {
+	command := "list"
+	if len(args) > 0 {
+		command = args[0]
+	}
+	if command != "list" && len(args) < 2 {
+		return fmt.Errorf("usage: river ai templates %s <name>", command)
+	}
+
+	switch command {
+	case "list":
+		fmt.Println("Prompt templates (override one by saving it to ~/river/prompts/<name>.md):")
+		fmt.Println()
+		for _, name := range ai.TemplateNames() {
+			t, err := ai.LoadTemplate(name)
+			if err != nil {
+				return err
+			}
+			source := "built-in"
+			if t.Path != "" {
+				source = t.Path
+			}
+			fmt.Printf("  %-10s %s\n  %-10s %s\n", t.Name, t.Description, "", source)
+		}
+		fmt.Println()
+		fmt.Println("Templates can use {{.Notes}}, {{.Start}}, {{.End}}, {{.Days}}, {{.Today}} and,")
+		fmt.Println("for insights, {{.Stats}}. A line containing only --- separates the system")
+		fmt.Println("prompt from the message. Start from 'river ai templates show <name>'.")
+		return nil
+	case "show":
+		t, err := ai.LoadTemplate(args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Print(t.Source())
+		return nil
+	case "reset":
+		removed, err := ai.ResetTemplate(args[1])
+		if err != nil {
+			return err
+		}
+		if !removed {
+			fmt.Printf("%s already uses the built-in template\n", args[1])
+			return nil
+		}
+		fmt.Printf("↩️  %s is back to the built-in template (your version was kept as %s.md.bak)\n", args[1], args[1])
+		return nil
+	default:
+		return fmt.Errorf("unknown templates command %q, expected list, show or reset", command)
+	}
+}
+
 func main() {
 	// Check if this is the first run and API key is needed
 	if onboarding.NeedsOnboarding() && len(os.Args) == 1 {
@@ -48,32 +478,67 @@ func main() {
 	if len(os.Args) > 1 {
 		switch os.Args[1] {
 		case "stats":
-			p := tea.NewProgram(statsui.InitModel(), tea.WithAltScreen())
-			if _, err := p.Run(); err != nil {
-				fmt.Printf("Error: %v", err)
+			if err := runStats(os.Args[2:]); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "rest":
+			if len(os.Args) < 3 {
+				fmt.Println("Usage: river rest <YYYY-MM-DD>")
+				os.Exit(1)
+			}
+			date, err := time.ParseInLocation("2006-01-02", os.Args[2], time.Local)
+			if err != nil {
+				fmt.Printf("Error: invalid date %q, expected YYYY-MM-DD\n", os.Args[2])
 				os.Exit(1)
 			}
+			used, err := streak.MarkRest(date)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("❄️  %s marked as a rest day (%d freeze(s) used in %s)\n",
+				date.Format("Mon, Jan 2"), used, date.Format("January"))
 			return
 		case "think":
-			if err := ai.GenerateTodos(); err != nil {
+			if err := runAICommand("think", os.Args[2:], ai.GenerateTodos); err != nil {
 				fmt.Printf("Error: %v\n", err)
 				os.Exit(1)
 			}
 			return
 		case "analyze":
-			if err := ai.GenerateInsights(); err != nil {
+			if err := runAICommand("analyze", os.Args[2:], ai.GenerateInsights); err != nil {
 				fmt.Printf("Error: %v\n", err)
 				os.Exit(1)
 			}
 			return
 		case "todo":
-			if err := ai.GenerateSimpleTodos(); err != nil {
+			if err := runAICommand("todo", os.Args[2:], ai.GenerateSimpleTodos); err != nil {
 				fmt.Printf("Error: %v\n", err)
 				os.Exit(1)
 			}
 			return
 		case "prompts":
-			if err := ai.GeneratePrompts(); err != nil {
+			if err := runAICommand("prompts", os.Args[2:], ai.GeneratePrompts); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "chat":
+			if err := runChat(os.Args[2:]); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "search":
+			if err := runSearch(os.Args[2:]); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "ai":
+			if err := runAI(os.Args[2:]); err != nil {
 				fmt.Printf("Error: %v\n", err)
 				os.Exit(1)
 			}
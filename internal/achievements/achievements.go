@@ -0,0 +1,375 @@
+package achievements
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/mattwhite/river-go/internal/config"
+	"github.com/mattwhite/river-go/internal/session"
+	"github.com/mattwhite/river-go/internal/statscache"
+	"github.com/mattwhite/river-go/internal/streak"
+)
+
+// Metric is what an achievement measures.
+type Metric int
+
+const (
+	TotalWords Metric = iota
+	Entries
+	Streak
+	BestDay
+	GoalDays
+)
+
+// Achievement is a milestone reached once a metric hits its target.
+type Achievement struct {
+	ID     string
+	Icon   string
+	Name   string
+	Metric Metric
+	Target int
+}
+
+// All lists every achievement, easiest first within each metric.
+var All = []Achievement{
+	{"first-entry", "✍️", "First entry", Entries, 1},
+	{"entries-10", "📓", "10 entries", Entries, 10},
+	{"entries-50", "📓", "50 entries", Entries, 50},
+	{"entries-100", "📚", "100 entries", Entries, 100},
+	{"entries-365", "📚", "365 entries", Entries, 365},
+	{"words-1k", "🌱", "1,000 words", TotalWords, 1000},
+	{"words-10k", "🌿", "10,000 words", TotalWords, 10000},
+	{"words-50k", "🌳", "50,000 words", TotalWords, 50000},
+	{"words-100k", "🌲", "100,000 words", TotalWords, 100000},
+	{"words-250k", "🏔️", "250,000 words", TotalWords, 250000},
+	{"words-1m", "🌌", "A million words", TotalWords, 1000000},
+	{"streak-7", "🔥", "7-day streak", Streak, 7},
+	{"streak-30", "🔥", "30-day streak", Streak, 30},
+	{"streak-100", "☄️", "100-day streak", Streak, 100},
+	{"streak-365", "☀️", "365-day streak", Streak, 365},
+	{"day-1000", "⚡", "1,000-word day", BestDay, 1000},
+	{"day-2500", "⚡", "2,500-word day", BestDay, 2500},
+	{"goals-10", "🎯", "Goal met 10 times", GoalDays, 10},
+	{"goals-50", "🎯", "Goal met 50 times", GoalDays, 50},
+	{"goals-100", "🏆", "Goal met 100 times", GoalDays, 100},
+}
+
+// Day is one entry's contribution to the metrics.
+type Day struct {
+	Date       time.Time
+	Words      int
+	TypingTime time.Duration
+}
+
+// Status is how far along an achievement is.
+type Status struct {
+	Achievement
+	Value   int
+	Reached time.Time // Zero until reached
+}
+
+// Progress returns the value from 0 to 1.
+func (s Status) Progress() float64 {
+	return min(1, float64(s.Value)/float64(s.Target))
+}
+
+// Record is the best single day so far.
+type Record struct {
+	Date  string `json:"date"`
+	Words int    `json:"words"`
+}
+
+// Store holds what has been unlocked, so dates survive edits to old entries.
+type Store struct {
+	Unlocked map[string]string `json:"unlocked"` // ID -> 2006-01-02
+	BestDay  Record            `json:"best_day"`
+}
+
+func storePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, "river", "achievements.json"), nil
+}
+
+// Load reads the store. A missing store is empty.
+func Load() (*Store, error) {
+	store := &Store{Unlocked: make(map[string]string)}
+
+	path, err := storePath()
+	if err != nil {
+		return store, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return store, err
+	}
+	if err := json.Unmarshal(data, store); err != nil {
+		return store, err
+	}
+	if store.Unlocked == nil {
+		store.Unlocked = make(map[string]string)
+	}
+	return store, nil
+}
+
+// Save writes the store.
+func (s *Store) Save() error {
+	path, err := storePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Record stores newly reached achievements and a new best day, and returns
+// messages celebrating them. A best day is only celebrated when it beats one
+// from an earlier day.
+func (s *Store) Record(statuses []Status, best Record, today time.Time) []string {
+	var messages []string
+	for _, status := range statuses {
+		if status.Reached.IsZero() {
+			continue
+		}
+		if _, ok := s.Unlocked[status.ID]; ok {
+			continue
+		}
+		s.Unlocked[status.ID] = status.Reached.Format("2006-01-02")
+		messages = append(messages, fmt.Sprintf("%s %s unlocked!", status.Icon, status.Name))
+	}
+
+	if best.Words > s.BestDay.Words {
+		if s.BestDay.Words > 0 && s.BestDay.Date != today.Format("2006-01-02") && best.Date == today.Format("2006-01-02") {
+			messages = append(messages, fmt.Sprintf("🏅 New personal best: %d words!", best.Words))
+		}
+		s.BestDay = best
+	}
+
+	return messages
+}
+
+// Evaluate replays days in order and returns every achievement's status,
+// along with the best day. Days may be in any order.
+func Evaluate(days []Day, goals config.Goals, rules streak.Rules, today time.Time) ([]Status, Record) {
+	statuses := make([]Status, len(All))
+	for i, a := range All {
+		statuses[i] = Status{Achievement: a}
+	}
+
+	sorted := make([]Day, 0, len(days))
+	for _, day := range days {
+		if day.Words > 0 {
+			sorted = append(sorted, day)
+		}
+	}
+	if len(sorted) == 0 {
+		return statuses, Record{}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+
+	written := make(map[string]bool)
+	for _, day := range sorted {
+		if goals.CountsForStreak(day.Date, day.Words, day.TypingTime) {
+			written[day.Date.Format("2006-01-02")] = true
+		}
+	}
+	calendar := streak.Compute(written, sorted[0].Date, today, rules)
+
+	var values [GoalDays + 1]int
+	var best Record
+	for _, day := range sorted {
+		if day.Words > values[BestDay] {
+			best = Record{Date: day.Date.Format("2006-01-02"), Words: day.Words}
+		}
+		values = addDay(values, day, goals, calendar)
+
+		for i := range statuses {
+			s := &statuses[i]
+			s.Value = values[s.Metric]
+			if s.Reached.IsZero() && s.Value >= s.Target {
+				s.Reached = day.Date
+			}
+		}
+	}
+
+	return statuses, best
+}
+
+// addDay returns values with day's contribution added.
+func addDay(values [GoalDays + 1]int, day Day, goals config.Goals, calendar streak.Calendar) [GoalDays + 1]int {
+	values[TotalWords] += day.Words
+	values[Entries]++
+	values[Streak] = max(values[Streak], calendar.StreakOn(day.Date))
+	values[BestDay] = max(values[BestDay], day.Words)
+	if goals.Met(day.Date, day.Words, day.TypingTime) {
+		values[GoalDays]++
+	}
+	return values
+}
+
+// LoadDays returns every entry from the stats cache with its typing time.
+func LoadDays() ([]Day, error) {
+	entries, err := statscache.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := session.LoadAll()
+	if err != nil {
+		sessions = map[string][]session.Session{}
+	}
+
+	days := make([]Day, 0, len(entries))
+	for _, entry := range entries {
+		date, err := time.Parse("2006-01-02", entry.Date)
+		if err != nil {
+			continue
+		}
+		var typingTime time.Duration
+		for _, s := range sessions[entry.Date] {
+			typingTime += s.ActiveTime()
+		}
+		days = append(days, Day{Date: date, Words: entry.Words, TypingTime: typingTime})
+	}
+	return days, nil
+}
+
+// Tracker watches one entry being written and reports milestones as they are
+// crossed. Everything that doesn't depend on the entry is worked out once, so
+// each update only adds the entry's contribution.
+type Tracker struct {
+	date      time.Time
+	before    [2][GoalDays + 1]int // Values from entries before date, by whether the entry counts towards a streak
+	after     []Day                // Entries after date, in order
+	calendars [2]streak.Calendar   // Without and with the entry counting towards a streak
+	best      Record               // Best day among the other entries
+	store     *Store
+	goals     config.Goals
+	rules     streak.Rules
+}
+
+// NewTracker returns a tracker for the entry on date. Anything already
+// reached is recorded quietly, so only crossings made while writing are
+// celebrated.
+func NewTracker(date time.Time, words int, typingTime time.Duration) *Tracker {
+	t := &Tracker{
+		date:  date,
+		goals: config.LoadGoals(),
+		rules: streak.LoadRules(),
+	}
+	t.store, _ = Load()
+
+	all, _ := LoadDays()
+	dateStr := date.Format("2006-01-02")
+	var others []Day
+	for _, day := range all {
+		if day.Words > 0 && day.Date.Format("2006-01-02") != dateStr {
+			others = append(others, day)
+		}
+	}
+	sort.Slice(others, func(i, j int) bool {
+		return others[i].Date.Before(others[j].Date)
+	})
+
+	entry := Day{Date: date, Words: words, TypingTime: typingTime}
+	statuses, best := Evaluate(append(others, entry), t.goals, t.rules, time.Now())
+	t.store.Record(statuses, best, date)
+	t.store.Save()
+
+	written := make(map[string]bool)
+	first := date
+	for _, day := range others {
+		if t.goals.CountsForStreak(day.Date, day.Words, day.TypingTime) {
+			written[day.Date.Format("2006-01-02")] = true
+		}
+		if day.Date.Before(first) {
+			first = day.Date
+		}
+	}
+	t.calendars[0] = streak.Compute(written, first, time.Now(), t.rules)
+	written[dateStr] = true
+	t.calendars[1] = streak.Compute(written, first, time.Now(), t.rules)
+
+	for _, day := range others {
+		if day.Date.After(date) {
+			t.after = append(t.after, day)
+			continue
+		}
+		for i := range t.before {
+			t.before[i] = addDay(t.before[i], day, t.goals, t.calendars[i])
+		}
+		if day.Words > t.best.Words {
+			t.best = Record{Date: day.Date.Format("2006-01-02"), Words: day.Words}
+		}
+	}
+	for _, day := range t.after {
+		if day.Words > t.best.Words {
+			t.best = Record{Date: day.Date.Format("2006-01-02"), Words: day.Words}
+		}
+	}
+
+	return t
+}
+
+// Update evaluates the entry at its current length and returns messages for
+// any milestones it just crossed.
+func (t *Tracker) Update(words int, typingTime time.Duration) []string {
+	counts := 0
+	if t.goals.CountsForStreak(t.date, words, typingTime) {
+		counts = 1
+	}
+	calendar := t.calendars[counts]
+	before := t.before[counts]
+
+	statuses := make([]Status, len(All))
+	for i, a := range All {
+		statuses[i] = Status{Achievement: a}
+	}
+
+	// Only achievements the other entries haven't reached can be crossed, and
+	// the first day they're reached on is the entry or one after it
+	values := before
+	days := t.after
+	if words > 0 {
+		days = append([]Day{{Date: t.date, Words: words, TypingTime: typingTime}}, t.after...)
+	}
+	for _, day := range days {
+		values = addDay(values, day, t.goals, calendar)
+		for i := range statuses {
+			s := &statuses[i]
+			s.Value = values[s.Metric]
+			if s.Reached.IsZero() && before[s.Metric] < s.Target && s.Value >= s.Target {
+				s.Reached = day.Date
+			}
+		}
+	}
+
+	best := t.best
+	if words > best.Words {
+		best = Record{Date: t.date.Format("2006-01-02"), Words: words}
+	}
+
+	messages := t.store.Record(statuses, best, t.date)
+	if len(messages) > 0 {
+		// Losing a celebration to a failed write isn't worth interrupting for
+		t.store.Save()
+	}
+	return messages
+}
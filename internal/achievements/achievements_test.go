@@ -0,0 +1,185 @@
+package achievements
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattwhite/river-go/internal/config"
+	"github.com/mattwhite/river-go/internal/streak"
+)
+
+// daysAgo returns the date n days before today, as entries are dated.
+func daysAgo(n int) time.Time {
+	date, _ := time.Parse("2006-01-02", time.Now().AddDate(0, 0, -n).Format("2006-01-02"))
+	return date
+}
+
+// setupJournal makes a home directory holding config and an entry with the
+// given number of words on each day, keyed by how many days ago it was.
+func setupJournal(t *testing.T, config string, entries map[int]int) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	notes := filepath.Join(home, "river", "notes")
+	if err := os.MkdirAll(notes, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, "river", ".config"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for ago, words := range entries {
+		text := strings.Repeat("word ", words)
+		if err := os.WriteFile(filepath.Join(notes, daysAgo(ago).Format("2006-01-02")+".md"), []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// evaluateAll is what Tracker replaces: every update evaluates every entry
+// from scratch.
+type evaluateAll struct {
+	date   time.Time
+	others []Day
+	store  *Store
+	goals  config.Goals
+	rules  streak.Rules
+}
+
+func newEvaluateAll(t *testing.T, date time.Time, words int) *evaluateAll {
+	all, err := LoadDays()
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := &evaluateAll{
+		date:  date,
+		store: &Store{Unlocked: make(map[string]string)},
+		goals: config.LoadGoals(),
+		rules: streak.LoadRules(),
+	}
+	for _, day := range all {
+		if !day.Date.Equal(date) {
+			e.others = append(e.others, day)
+		}
+	}
+	e.update(words)
+	return e
+}
+
+func (e *evaluateAll) update(words int) []string {
+	days := append(e.others[:len(e.others):len(e.others)], Day{Date: e.date, Words: words})
+	statuses, best := Evaluate(days, e.goals, e.rules, time.Now())
+	return e.store.Record(statuses, best, e.date)
+}
+
+func TestTrackerMatchesEvaluate(t *testing.T) {
+	// Seven days of 100 words, with a day missing in the middle
+	bridged := map[int]int{8: 100, 7: 100, 6: 100, 4: 100, 3: 100, 2: 100, 1: 100}
+
+	tests := []struct {
+		name    string
+		config  string
+		entries map[int]int
+		entry   int   // Days ago the entry being written is dated
+		words   []int // Word counts as the entry is written
+		want    [][]string
+	}{
+		{
+			name:    "today's entry",
+			entries: map[int]int{3: 300, 2: 300, 1: 300},
+			words:   []int{0, 50, 200, 120, 1100},
+			want: [][]string{
+				nil,
+				nil,
+				{"🌱 1,000 words unlocked!"},
+				nil,
+				{"⚡ 1,000-word day unlocked!", "🏅 New personal best: 1100 words!"},
+			},
+		},
+		{
+			name:    "backdated entry",
+			entries: map[int]int{16: 500, 12: 500, 11: 500, 9: 500, 8: 500, 7: 500, 6: 500, 5: 500, 4: 500},
+			entry:   10,
+			words:   []int{0, 10, 4800, 5000},
+			want: [][]string{
+				nil,
+				{"📓 10 entries unlocked!", "🔥 7-day streak unlocked!"},
+				{"⚡ 1,000-word day unlocked!", "⚡ 2,500-word day unlocked!", "🎯 Goal met 10 times unlocked!", "🏅 New personal best: 4800 words!"},
+				nil,
+			},
+		},
+		{
+			name:    "entry bridges a streak",
+			entries: bridged,
+			entry:   5,
+			words:   []int{0, 5},
+			want:    [][]string{nil, {"🔥 7-day streak unlocked!"}},
+		},
+		{
+			name:    "streak needs the goal met",
+			config:  "DAILY_GOAL=100\nSTREAK_REQUIRES_GOAL=true\n",
+			entries: bridged,
+			entry:   5,
+			words:   []int{0, 60, 99, 100, 80},
+			want:    [][]string{nil, nil, nil, {"🔥 7-day streak unlocked!"}, nil},
+		},
+		{
+			name:    "personal best once a day",
+			entries: map[int]int{2: 400, 1: 600},
+			words:   []int{500, 700, 900, 1100},
+			want: [][]string{
+				nil,
+				{"🏅 New personal best: 700 words!"},
+				nil,
+				{"⚡ 1,000-word day unlocked!"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupJournal(t, tt.config, tt.entries)
+			date := daysAgo(tt.entry)
+
+			tracker := NewTracker(date, tt.words[0], 0)
+			reference := newEvaluateAll(t, date, tt.words[0])
+			for i, words := range tt.words[1:] {
+				got := tracker.Update(words, 0)
+				want := reference.update(words)
+				if !sameMessages(got, want) {
+					t.Errorf("at %d words Update = %q, evaluating everything gives %q", words, got, want)
+				}
+				if !sameMessages(got, tt.want[i+1]) {
+					t.Errorf("at %d words Update = %q, want %q", words, got, tt.want[i+1])
+				}
+			}
+			if !reflect.DeepEqual(tracker.store.Unlocked, reference.store.Unlocked) {
+				t.Errorf("unlocked %v, evaluating everything gives %v", tracker.store.Unlocked, reference.store.Unlocked)
+			}
+			if tracker.store.BestDay != reference.store.BestDay {
+				t.Errorf("best day %v, evaluating everything gives %v", tracker.store.BestDay, reference.store.BestDay)
+			}
+		})
+	}
+}
+
+// sameMessages compares messages ignoring their order, which follows All.
+func sameMessages(a, b []string) bool {
+	count := make(map[string]int)
+	for _, s := range a {
+		count[s]++
+	}
+	for _, s := range b {
+		count[s]--
+	}
+	for _, n := range count {
+		if n != 0 {
+			return false
+		}
+	}
+	return true
+}
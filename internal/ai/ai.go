@@ -8,362 +8,43 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
-
-	"github.com/anthropics/anthropic-sdk-go"
-	"github.com/mattwhite/river-go/internal/onboarding"
 )
 
-// loadAPIKey loads the API key from environment or config file
-func loadAPIKey() string {
-	// First check environment variable
-	if apiKey := os.Getenv("ANTHROPIC_API_KEY"); apiKey != "" {
-		return apiKey
-	}
-
-	// Then check config file
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return ""
-	}
-
-	configPath := filepath.Join(homeDir, "river", ".config")
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		return ""
-	}
-
-	lines := strings.Split(string(data), "\n")
-	for _, line := range lines {
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) == 2 && parts[0] == "ANTHROPIC_API_KEY" {
-			return strings.TrimSpace(parts[1])
-		}
-	}
-
-	return ""
-}
-
-// getRecentNotes reads notes from the last few days
-func getRecentNotes(days int) (string, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
-	}
-
-	riverDir := filepath.Join(homeDir, "river", "notes")
-
-	var allContent strings.Builder
-
-	for i := 0; i < days; i++ {
-		date := time.Now().AddDate(0, 0, -i)
-		dateStr := date.Format("2006-01-02")
-		filename := filepath.Join(riverDir, dateStr+".md")
-
-		content, err := os.ReadFile(filename)
-		if err != nil {
-			if os.IsNotExist(err) {
-				continue
-			}
-			return "", err
-		}
-
-		lines := strings.Split(string(content), "\n")
-		var filteredLines []string
-		for _, line := range lines {
-			trimmed := strings.TrimSpace(line)
-			if strings.HasPrefix(trimmed, "<!--") && strings.HasSuffix(trimmed, "-->") {
-				continue
-			}
-			if strings.TrimSpace(line) != "" {
-				filteredLines = append(filteredLines, line)
-			}
-		}
-
-		if len(filteredLines) > 0 {
-			allContent.WriteString(fmt.Sprintf("\n=== %s ===\n", date.Format("Monday, January 2, 2006")))
-			allContent.WriteString(strings.Join(filteredLines, "\n"))
-			allContent.WriteString("\n")
-		}
-	}
-
-	return allContent.String(), nil
-}
-
-func callAnthropic(prompt string) (string, error) {
-	apiKey := loadAPIKey()
-	if apiKey == "" {
-		return "", fmt.Errorf("No API key found. Run 'river onboard' to set up AI features")
-	}
-	// Set the API key in environment for the client
-	os.Setenv("ANTHROPIC_API_KEY", apiKey)
-	client := anthropic.NewClient()
-	systemPrompt := `You are an AI assistant helping with personal productivity. Based on the provided notes from the last few days, generate organized lists of TODOs in different categories.
-
-Create the following sections:
-
-**WORK TODOs:**
-- Tasks related to professional projects, meetings, deadlines
-- Follow-ups with colleagues or clients
-- Work-related goals and commitments
-
-**PERSONAL TODOs:**
-- Personal tasks, household items, family commitments
-- Health, fitness, and self-care items
-- Financial and administrative tasks
-
-**PROJECTS & IDEAS:**
-- Ideas that need further exploration or research
-- Long-term goals and strategic thinking
-- Creative projects or learning opportunities
-- Reflection and planning items
-
-Focus on:
-- Incomplete tasks or projects mentioned
-- Follow-ups needed 
-- Ideas that could be developed further
-- Goals or commitments that need action
-- Any deadlines or time-sensitive items
-
-IMPORTANT: For each TODO item, include a brief rationale in parentheses that cites or references the specific note content that led to this suggestion. For example:
-"1. Follow up with John about the project proposal (mentioned meeting him on Tuesday but no follow-up scheduled)"
-
-Format your response with clear section headers and numbered lists under each. Be specific and concise. If a category has no clear TODOs, you may omit that section or suggest general productivity actions based on the content themes.`
-	userPrompt := fmt.Sprintf("Here are my notes from the last few days:\n\n%s\n\nPlease generate organized lists of TODOs based on this content, categorized by Work, Home, and Deeper Thought items.", prompt)
-	ctx := context.Background()
-	response, err := client.Messages.New(ctx, anthropic.MessageNewParams{
-		Model:     "claude-sonnet-4-20250514",
-		MaxTokens: 1000,
-		System:    []anthropic.TextBlockParam{{Text: systemPrompt, Type: "text"}},
-		Messages: []anthropic.MessageParam{
-			{
-				Role: anthropic.MessageParamRoleUser,
-				Content: []anthropic.ContentBlockParamUnion{
-					{OfText: &anthropic.TextBlockParam{Text: userPrompt, Type: "text"}},
-				},
-			},
-		},
-	})
-	if err != nil {
-		return "", fmt.Errorf("Anthropic API error: %v", err)
-	}
-	if len(response.Content) == 0 {
-		return "", fmt.Errorf("no response content from Anthropic")
-	}
-	for _, content := range response.Content {
-		if content.Type == "text" && content.Text != "" {
-			return content.Text, nil
-		}
-	}
-	return "", fmt.Errorf("unexpected response format from Anthropic")
-}
-
-func callAnthropicForInsights(prompt string) (string, error) {
-	apiKey := loadAPIKey()
-	if apiKey == "" {
-		return "", fmt.Errorf("No API key found. Run 'river onboard' to set up AI features")
-	}
-	// Set the API key in environment for the client
-	os.Setenv("ANTHROPIC_API_KEY", apiKey)
-	client := anthropic.NewClient()
-	systemPrompt := `You are an AI assistant specialized in analyzing personal notes to identify patterns, themes, and insights. Based on the provided notes from the last few days, provide a thoughtful analysis.
-
-Please create the following sections:
-
-**THEMES & PATTERNS:**
-- Recurring topics or concerns that appear across multiple days
-- Emotional patterns or mood trends you notice
-- Productivity or energy level patterns
-- Common challenges or obstacles mentioned
-
-**KEY INSIGHTS:**
-- What the notes reveal about current priorities and focus areas
-- Potential blind spots or areas that might need attention
-- Connections between different thoughts or ideas
-- Progress or changes you can observe over time
-
-**OBSERVATIONS:**
-- Notable differences between days or shifts in thinking
-- Areas where there seems to be mental clarity vs confusion
-- Signs of growth, learning, or development
-- Recurring questions or curiosities
-
-Be thoughtful and nuanced in your analysis. Focus on helping the person understand their own thinking patterns and mental landscape. Cite specific examples from the notes when possible to support your observations.
-
-Format your response with clear section headers and insightful commentary. Be encouraging and constructive while being honest about what you observe.`
-	userPrompt := fmt.Sprintf("Here are my notes from the last few days:\n\n%s\n\nPlease analyze these notes for patterns, themes, and insights about my thinking and mental state.", prompt)
-	ctx := context.Background()
-	response, err := client.Messages.New(ctx, anthropic.MessageNewParams{
-		Model:     "claude-3-haiku-20240307",
-		MaxTokens: 1200,
-		System:    []anthropic.TextBlockParam{{Text: systemPrompt, Type: "text"}},
-		Messages: []anthropic.MessageParam{{
-			Role:    anthropic.MessageParamRoleUser,
-			Content: []anthropic.ContentBlockParamUnion{{OfText: &anthropic.TextBlockParam{Text: userPrompt, Type: "text"}}},
-		}},
-	})
-	if err != nil {
-		return "", fmt.Errorf("Anthropic API error: %v", err)
-	}
-	if len(response.Content) == 0 {
-		return "", fmt.Errorf("no response content from Anthropic")
-	}
-	for _, content := range response.Content {
-		if content.Type == "text" && content.Text != "" {
-			return content.Text, nil
-		}
-	}
-	return "", fmt.Errorf("unexpected response format from Anthropic")
-}
-
-func callAnthropicForSimpleTodos(prompt string) (string, error) {
-	apiKey := loadAPIKey()
-	if apiKey == "" {
-		return "", fmt.Errorf("No API key found. Run 'river onboard' to set up AI features")
-	}
-	// Set the API key in environment for the client
-	os.Setenv("ANTHROPIC_API_KEY", apiKey)
-	client := anthropic.NewClient()
-	systemPrompt := `Extract ONLY the most concrete, actionable TODOs from the notes. Be extremely selective - only include items that:
-
-1. Are EXPLICITLY mentioned as tasks, commitments, or things to do
-2. Have a clear, specific action (call someone, buy something, schedule something, send something)
-3. Can be completed in a single, definable action
-
-DO NOT include:
-- Vague goals or aspirations
-- Things to "think about" or "consider"
-- Emotional work or self-improvement items
-- Ideas without clear next steps
-
-Format each TODO as:
-• [ACTION VERB] [SPECIFIC TASK]
-
-Examples of GOOD todos:
-• Call dentist to schedule cleaning
-• Send Q4 report to Sarah
-• Buy new running shoes
-• Schedule meeting with product team
-• Submit expense report for conference
-
-Examples of BAD todos (do not include):
-• Think about career goals
-• Be more organized
-• Work on project
-• Improve communication
-• Consider new strategies
-
-Keep the list SHORT (max 10 items). If there are no truly actionable items, return "No specific action items found in recent notes."
-
-Sort by urgency/importance when possible.`
-	userPrompt := fmt.Sprintf("Here are my notes from the last few days:\n\n%s\n\nPlease extract actionable TODOs from these notes.", prompt)
-	ctx := context.Background()
-	response, err := client.Messages.New(ctx, anthropic.MessageNewParams{
-		Model:     "claude-3-haiku-20240307",
-		MaxTokens: 800,
-		System:    []anthropic.TextBlockParam{{Text: systemPrompt, Type: "text"}},
-		Messages: []anthropic.MessageParam{{
-			Role:    anthropic.MessageParamRoleUser,
-			Content: []anthropic.ContentBlockParamUnion{{OfText: &anthropic.TextBlockParam{Text: userPrompt, Type: "text"}}},
-		}},
-	})
+func requestPrompts(ctx context.Context, days []dayNotes, r noteRange, settings CommandSettings, out *printer) ([]string, time.Time, error) {
+	data := newTemplateData(days, r)
+	text, cachedAt, err := ask(ctx, settings, data, r.scope(), out)
 	if err != nil {
-		return "", fmt.Errorf("Anthropic API error: %v", err)
-	}
-	if len(response.Content) == 0 {
-		return "", fmt.Errorf("no response content from Anthropic")
-	}
-	for _, content := range response.Content {
-		if content.Type == "text" && content.Text != "" {
-			return content.Text, nil
-		}
-	}
-	return "", fmt.Errorf("unexpected response format from Anthropic")
-}
-
-func callAnthropicForPrompts(notes string) ([]string, error) {
-	apiKey := loadAPIKey()
-	if apiKey == "" {
-		return nil, fmt.Errorf("No API key found. Run 'river onboard' to set up AI features")
-	}
-	// Set the API key in environment for the client
-	os.Setenv("ANTHROPIC_API_KEY", apiKey)
-	client := anthropic.NewClient()
-	systemPrompt := `You are an AI assistant that creates personalized journal prompts based on someone's recent journal entries. Your goal is to help them reflect more deeply, explore unresolved thoughts, and continue their personal growth journey.
-
-Based on their recent notes, generate 7 thoughtful journal prompts that:
-
-1. Build on themes and topics they've been exploring
-2. Help them dig deeper into unresolved questions or concerns
-3. Encourage reflection on patterns you notice
-4. Challenge them to think about things from new perspectives
-5. Support their goals and aspirations
-6. Address any emotional or mental patterns you observe
-7. Connect different ideas they've mentioned
-
-Guidelines:
-- Make prompts specific to their content, not generic
-- Reference specific topics, people, or situations they've mentioned when relevant
-- Vary the types of prompts (reflection, planning, gratitude, challenge, insight, etc.)
-- Keep prompts open-ended but focused
-- Make them thought-provoking but not overwhelming
-- Consider their current emotional state and energy level
-
-Format your response as a JSON array of strings, with exactly 7 prompts. Each prompt should be a complete question or writing prompt. Example format:
-["First prompt here?", "Second prompt here?", "Third prompt here?", "Fourth prompt here?", "Fifth prompt here?", "Sixth prompt here?", "Seventh prompt here?"]`
-	userPrompt := fmt.Sprintf("Here are my journal entries from the last week:\n\n%s\n\nPlease generate 7 personalized journal prompts based on these entries.", notes)
-	ctx := context.Background()
-	response, err := client.Messages.New(ctx, anthropic.MessageNewParams{
-		Model:     "claude-3-haiku-20240307",
-		MaxTokens: 800,
-		System:    []anthropic.TextBlockParam{{Text: systemPrompt, Type: "text"}},
-		Messages: []anthropic.MessageParam{{
-			Role:    anthropic.MessageParamRoleUser,
-			Content: []anthropic.ContentBlockParamUnion{{OfText: &anthropic.TextBlockParam{Text: userPrompt, Type: "text"}}},
-		}},
-	})
-	if err != nil {
-		return nil, fmt.Errorf("Anthropic API error: %v", err)
-	}
-	if len(response.Content) == 0 {
-		return nil, fmt.Errorf("no response content from Anthropic")
-	}
-	for _, content := range response.Content {
-		if content.Type == "text" && content.Text != "" {
-			var prompts []string
-			responseText := strings.TrimSpace(content.Text)
-			startIdx := strings.Index(responseText, "[")
-			endIdx := strings.LastIndex(responseText, "]")
-			if startIdx != -1 && endIdx != -1 && endIdx > startIdx {
-				jsonStr := responseText[startIdx : endIdx+1]
-				if err := json.Unmarshal([]byte(jsonStr), &prompts); err != nil {
-					jsonStr = strings.Trim(jsonStr, "[]")
-					parts := strings.Split(jsonStr, "\", \"")
-					for _, part := range parts {
-						part = strings.Trim(part, "\"")
-						part = strings.ReplaceAll(part, "\\\"", "\"")
-						if part != "" {
-							prompts = append(prompts, part)
-						}
-					}
+		return nil, cachedAt, err
+	}
+	var prompts []string
+	responseText := strings.TrimSpace(text)
+	startIdx := strings.Index(responseText, "[")
+	endIdx := strings.LastIndex(responseText, "]")
+	if startIdx != -1 && endIdx != -1 && endIdx > startIdx {
+		jsonStr := responseText[startIdx : endIdx+1]
+		if err := json.Unmarshal([]byte(jsonStr), &prompts); err != nil {
+			jsonStr = strings.Trim(jsonStr, "[]")
+			parts := strings.Split(jsonStr, "\", \"")
+			for _, part := range parts {
+				part = strings.Trim(part, "\"")
+				part = strings.ReplaceAll(part, "\\\"", "\"")
+				if part != "" {
+					prompts = append(prompts, part)
 				}
 			}
-			if len(prompts) == 0 {
-				return nil, fmt.Errorf("could not parse prompts from response")
-			}
-			return prompts, nil
 		}
 	}
-	return nil, fmt.Errorf("unexpected response format from Anthropic")
+	if len(prompts) == 0 {
+		// Don't keep serving a reply that can't be used
+		forgetReply(settings, data, r.scope())
+		return nil, cachedAt, fmt.Errorf("could not parse prompts from response")
+	}
+	return prompts, cachedAt, nil
 }
 
-func callAnthropicForStatsInsights(stats AggregatedStats, recentNotes string) (string, error) {
-	apiKey := loadAPIKey()
-	if apiKey == "" {
-		return "", fmt.Errorf("No API key found. Run 'river onboard' to set up AI features")
-	}
-	// Set the API key in environment for the client
-	os.Setenv("ANTHROPIC_API_KEY", apiKey)
-	client := anthropic.NewClient()
+// requestStatsInsights analyzes stats alongside the notes in r. scope tells
+// apart analyses of different ranges of stats.
+func requestStatsInsights(ctx context.Context, stats AggregatedStats, days []dayNotes, r noteRange, scope string, settings CommandSettings) (string, time.Time, error) {
 	statsSummary := fmt.Sprintf(`Writing Statistics Summary:
 - Total Words Written: %d
 - Total Writing Time: %s
@@ -386,204 +67,190 @@ Recent Writing Activity (Last 14 Days):
 		statsSummary += fmt.Sprintf("- %s: %d words in %s\n",
 			stat.Date.Format("Mon, Jan 2"), stat.Words, formatDuration(stat.TypingTime))
 	}
-	systemPrompt := `You are an AI assistant that analyzes writing habits and journal statistics to provide personalized insights. Based on the provided statistics and recent journal entries, create a comprehensive analysis that helps the writer understand their patterns and improve their practice.
-
-Please provide insights in the following sections:
-
-**📊 PRODUCTIVITY PATTERNS**
-- Analyze writing frequency, volume trends, and time patterns
-- Identify peak productivity days/times if evident
-- Comment on consistency and streak patterns
-- Note any concerning gaps or declines
-
-**🎯 HABITS & CONSISTENCY**
-- Evaluate the strength of their writing habit
-- Comment on their streak performance
-- Suggest ways to improve consistency
-- Recognize achievements and milestones
-
-**💭 CONTENT THEMES**
-- Based on recent entries, identify recurring themes or concerns
-- Note any emotional patterns or mood trends
-- Highlight areas of focus or preoccupation
-- Suggest unexplored topics they might benefit from
-
-**🚀 RECOMMENDATIONS**
-- Provide 3-5 specific, actionable suggestions
-- Include both habit-building and content-focused advice
-- Suggest optimal writing times or goals based on their data
-- Recommend prompts or exercises based on their patterns
+	data := newTemplateData(days, r)
+	data.Stats = statsSummary
+	// New stats call for a new analysis, even if the notes are the same
+	return ask(ctx, settings, data, scope+"\x00"+statsSummary, nil)
+}
 
-Keep the tone encouraging but honest. Use data to support observations. Make recommendations specific and achievable. Format with clear headers and bullet points.`
-	userPrompt := fmt.Sprintf("%s\n\nRecent Journal Entries:\n%s\n\nPlease analyze my writing patterns and provide personalized insights.", statsSummary, recentNotes)
-	ctx := context.Background()
-	response, err := client.Messages.New(ctx, anthropic.MessageNewParams{
-		Model:     "claude-3-haiku-20240307",
-		MaxTokens: 1500,
-		System:    []anthropic.TextBlockParam{{Text: systemPrompt, Type: "text"}},
-		Messages: []anthropic.MessageParam{{
-			Role:    anthropic.MessageParamRoleUser,
-			Content: []anthropic.ContentBlockParamUnion{{OfText: &anthropic.TextBlockParam{Text: userPrompt, Type: "text"}}},
-		}},
-	})
+// RequestStatsInsights is an exported wrapper used by other packages. It
+// reads the notes in opts' window, or the last week without one. scope names
+// the range of stats, so each range's analysis is cached apart. Cancelling
+// ctx stops the request.
+func RequestStatsInsights(ctx context.Context, stats AggregatedStats, scope string, opts Options) (string, error) {
+	settings, err := LoadCommandSettings("insights", opts)
 	if err != nil {
-		return "", fmt.Errorf("Anthropic API error: %v", err)
+		return "", err
 	}
-	if len(response.Content) == 0 {
-		return "", fmt.Errorf("no response content from Anthropic")
+	r, err := opts.Window.resolve(insightsDays)
+	if err != nil {
+		return "", err
 	}
-	for _, content := range response.Content {
-		if content.Type == "text" && content.Text != "" {
-			return content.Text, nil
-		}
+	days, err := readNotes(r)
+	if err != nil {
+		return "", fmt.Errorf("error reading notes: %v", err)
 	}
-	return "", fmt.Errorf("unexpected response format from Anthropic")
-}
-
-// CallAnthropicForStatsInsights is an exported wrapper used by other packages.
-func CallAnthropicForStatsInsights(stats AggregatedStats, recentNotes string) (string, error) {
-	return callAnthropicForStatsInsights(stats, recentNotes)
+	text, cachedAt, err := requestStatsInsights(ctx, stats, days, r, scope, settings)
+	if err != nil && ctx.Err() != nil {
+		// Report a cancelled request as one, whatever the provider made of it
+		return "", ctx.Err()
+	}
+	if err != nil || !cachedAt.IsZero() {
+		return text, err
+	}
+	// The dashboard has nowhere to show a warning, and the reply is what matters
+	saveRun(newRun("insights", settings, formatNotes(days), r, text))
+	return text, nil
 }
 
-// Public command helpers (CLI-facing)
-func GenerateTodos() error {
+// Public command helpers (CLI-facing). Replies stream to the terminal, and
+// cancelling ctx stops the request.
+func GenerateTodos(ctx context.Context, opts Options) error {
+	settings, err := LoadCommandSettings("think", opts)
+	if err != nil {
+		return err
+	}
+	r, err := opts.Window.resolve(10)
+	if err != nil {
+		return err
+	}
 	fmt.Println("🤔 Thinking about your recent notes...")
-	notes, err := getRecentNotes(10)
+	days, err := readNotes(r)
 	if err != nil {
 		return fmt.Errorf("error reading recent notes: %v", err)
 	}
+	notes := formatNotes(days)
 	if strings.TrimSpace(notes) == "" {
-		fmt.Println("📝 No recent notes found. Try writing some thoughts first!")
+		fmt.Printf("📝 No notes found from %s. Try writing some thoughts first!\n", r.describe())
 		return nil
 	}
-	fmt.Println("📖 Analyzing notes from the last 10 days...")
-	todos, err := callAnthropic(notes)
-	if err != nil {
-		if strings.Contains(err.Error(), "No API key found") {
-			fmt.Println("\n🔑 API key not configured. Let's set it up now...\n")
-			if err := onboarding.RunOnboarding(); err != nil {
-				return fmt.Errorf("onboarding failed: %v", err)
-			}
-			// Try again after onboarding
-			fmt.Println("\n🔄 Retrying with your new API key...")
-			todos, err = callAnthropic(notes)
-			if err != nil {
-				return fmt.Errorf("error calling AI: %v", err)
-			}
-			fmt.Println("\n✨ Here are some TODOs based on your recent notes:")
-			fmt.Println(todos)
+	fmt.Printf("📖 Analyzing notes from %s...\n", r.describe())
+	return retryAfterOnboarding(func() error {
+		out := newPrinter("\n✨ Here are some TODOs based on your recent notes:\n", settings)
+		text, cachedAt, err := ask(ctx, settings, newTemplateData(days, r), r.scope(), out)
+		if err := out.finish(ctx, err); err != nil || ctx.Err() != nil {
+			return err
+		}
+		if !cachedAt.IsZero() {
+			printCacheNotice(cachedAt)
 			return nil
 		}
-		return fmt.Errorf("error calling AI: %v", err)
-	}
-	fmt.Println("\n✨ Here are some TODOs based on your recent notes:")
-	fmt.Println(todos)
-	return nil
+		recordRun("think", settings, notes, r, text)
+		return nil
+	})
 }
 
-func GenerateInsights() error {
+func GenerateInsights(ctx context.Context, opts Options) error {
+	settings, err := LoadCommandSettings("analyze", opts)
+	if err != nil {
+		return err
+	}
+	r, err := opts.Window.resolve(10)
+	if err != nil {
+		return err
+	}
 	fmt.Println("🔍 Analyzing your recent notes for insights...")
-	notes, err := getRecentNotes(10)
+	days, err := readNotes(r)
 	if err != nil {
 		return fmt.Errorf("error reading recent notes: %v", err)
 	}
+	notes := formatNotes(days)
 	if strings.TrimSpace(notes) == "" {
-		fmt.Println("📝 No recent notes found. Try writing some thoughts first!")
+		fmt.Printf("📝 No notes found from %s. Try writing some thoughts first!\n", r.describe())
 		return nil
 	}
 	fmt.Println("🧠 Identifying patterns and themes...")
-	insights, err := callAnthropicForInsights(notes)
-	if err != nil {
-		if strings.Contains(err.Error(), "No API key found") {
-			fmt.Println("\n🔑 API key not configured. Let's set it up now...\n")
-			if err := onboarding.RunOnboarding(); err != nil {
-				return fmt.Errorf("onboarding failed: %v", err)
-			}
-			// Try again after onboarding
-			fmt.Println("\n🔄 Retrying with your new API key...")
-			insights, err = callAnthropicForInsights(notes)
-			if err != nil {
-				return fmt.Errorf("error calling AI: %v", err)
-			}
-			fmt.Println("\n💡 Here are insights from your recent notes:")
-			fmt.Println(insights)
+	return retryAfterOnboarding(func() error {
+		out := newPrinter("\n💡 Here are insights from your recent notes:\n", settings)
+		text, cachedAt, err := ask(ctx, settings, newTemplateData(days, r), r.scope(), out)
+		if err := out.finish(ctx, err); err != nil || ctx.Err() != nil {
+			return err
+		}
+		if !cachedAt.IsZero() {
+			printCacheNotice(cachedAt)
 			return nil
 		}
-		return fmt.Errorf("error calling AI: %v", err)
-	}
-	fmt.Println("\n💡 Here are insights from your recent notes:")
-	fmt.Println(insights)
-	return nil
+		recordRun("analyze", settings, notes, r, text)
+		return nil
+	})
 }
 
-func GenerateSimpleTodos() error {
+func GenerateSimpleTodos(ctx context.Context, opts Options) error {
+	settings, err := LoadCommandSettings("todo", opts)
+	if err != nil {
+		return err
+	}
+	r, err := opts.Window.resolve(15)
+	if err != nil {
+		return err
+	}
 	fmt.Println("📋 Extracting TODOs from your recent notes...")
-	notes, err := getRecentNotes(15)
+	days, err := readNotes(r)
 	if err != nil {
 		return fmt.Errorf("error reading recent notes: %v", err)
 	}
+	notes := formatNotes(days)
 	if strings.TrimSpace(notes) == "" {
-		fmt.Println("📝 No recent notes found. Try writing some thoughts first!")
+		fmt.Printf("📝 No notes found from %s. Try writing some thoughts first!\n", r.describe())
 		return nil
 	}
-	fmt.Println("✅ Analyzing last 15 days of notes...")
-	todos, err := callAnthropicForSimpleTodos(notes)
-	if err != nil {
-		if strings.Contains(err.Error(), "No API key found") {
-			fmt.Println("\n🔑 API key not configured. Let's set it up now...\n")
-			if err := onboarding.RunOnboarding(); err != nil {
-				return fmt.Errorf("onboarding failed: %v", err)
-			}
-			// Try again after onboarding
-			fmt.Println("\n🔄 Retrying with your new API key...")
-			todos, err = callAnthropicForSimpleTodos(notes)
-			if err != nil {
-				return fmt.Errorf("error calling AI: %v", err)
-			}
-			fmt.Println("\n📝 ACTION ITEMS:\n")
-			fmt.Println(todos)
+	fmt.Printf("✅ Analyzing notes from %s...\n", r.describe())
+	return retryAfterOnboarding(func() error {
+		out := newPrinter("\n📝 ACTION ITEMS:\n\n", settings)
+		text, cachedAt, err := ask(ctx, settings, newTemplateData(days, r), r.scope(), out)
+		if err := out.finish(ctx, err); err != nil || ctx.Err() != nil {
+			return err
+		}
+		if !cachedAt.IsZero() {
+			printCacheNotice(cachedAt)
 			return nil
 		}
-		return fmt.Errorf("error calling AI: %v", err)
-	}
-	fmt.Println("\n📝 ACTION ITEMS:\n")
-	fmt.Println(todos)
-	return nil
+		recordRun("todo", settings, notes, r, text)
+		return nil
+	})
 }
 
-func GeneratePrompts() error {
+// GeneratePrompts waits for the whole reply, since the prompts arrive as JSON
+// that has to be parsed before it can be shown.
+func GeneratePrompts(ctx context.Context, opts Options) error {
+	settings, err := LoadCommandSettings("prompts", opts)
+	if err != nil {
+		return err
+	}
+	r, err := opts.Window.resolve(10)
+	if err != nil {
+		return err
+	}
 	fmt.Println("✨ Creating personalized prompts based on your recent writing...")
-	notes, err := getRecentNotes(10)
+	days, err := readNotes(r)
 	if err != nil {
 		return fmt.Errorf("error reading recent notes: %v", err)
 	}
+	notes := formatNotes(days)
 	if strings.TrimSpace(notes) == "" {
-		fmt.Println("📝 No recent notes found. Try writing some thoughts first!")
+		fmt.Printf("📝 No notes found from %s. Try writing some thoughts first!\n", r.describe())
 		return nil
 	}
-	fmt.Println("🔮 Analyzing your journal entries from the last 10 days...")
-	prompts, err := callAnthropicForPrompts(notes)
-	if err != nil {
-		if strings.Contains(err.Error(), "No API key found") {
-			fmt.Println("\n🔑 API key not configured. Let's set it up now...\n")
-			if err := onboarding.RunOnboarding(); err != nil {
-				return fmt.Errorf("onboarding failed: %v", err)
-			}
-			// Try again after onboarding
-			fmt.Println("\n🔄 Retrying with your new API key...")
-			prompts, err = callAnthropicForPrompts(notes)
-			if err != nil {
-				return fmt.Errorf("error calling AI: %v", err)
-			}
-			// Continue with the rest of the function
-			goto ProcessPrompts
-		}
-		return fmt.Errorf("error calling AI: %v", err)
+	fmt.Printf("🔮 Analyzing your journal entries from %s...\n", r.describe())
+	var prompts []string
+	var cachedAt time.Time
+	err = retryAfterOnboarding(func() error {
+		out := newPrinter("", settings)
+		var err error
+		prompts, cachedAt, err = requestPrompts(ctx, days, r, settings, out)
+		return out.finish(ctx, err)
+	})
+	if err != nil || prompts == nil {
+		return err
 	}
-ProcessPrompts:
-	fmt.Println("\n🌟 Here are personalized journal prompts based on your recent reflections:\n")
+	fmt.Print("\n🌟 Here are personalized journal prompts based on your recent reflections:\n\n")
+	var list strings.Builder
 	for i, prompt := range prompts {
 		fmt.Printf("%d. %s\n\n", i+1, prompt)
+		fmt.Fprintf(&list, "%d. %s\n", i+1, prompt)
+	}
+	if cachedAt.IsZero() {
+		recordRun("prompts", settings, notes, r, list.String())
 	}
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
@@ -602,12 +269,16 @@ ProcessPrompts:
 		fmt.Printf("\n💾 Prompts saved to %s\n", promptsFile)
 		fmt.Println("   These prompts will be used for your daily notes over the next week.")
 	}
+	if !cachedAt.IsZero() {
+		printCacheNotice(cachedAt)
+	}
 	fmt.Println("\n💡 Tip: Run 'river prompts' weekly to get fresh, personalized prompts!")
 	return nil
 }
 
-// GenerateNotesForInsights returns recent notes content (last 7 days) for use by the stats UI.
-func GenerateNotesForInsights() (string, error) { return getRecentNotes(7) }
+// insightsDays is how far back the stats Insights tab reads notes when it
+// shows all time.
+const insightsDays = 7
 
 // Shared types/utilities for stats insights
 type AggregatedStats struct {
@@ -0,0 +1,140 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
+)
+
+// defaultAnthropicModel is used when a request doesn't name a model.
+const defaultAnthropicModel = "claude-haiku-4-5"
+
+// anthropicProvider talks to the Anthropic Messages API.
+type anthropicProvider struct {
+	client anthropic.Client
+}
+
+func newAnthropicProvider(apiKey, baseURL string) *anthropicProvider {
+	opts := []option.RequestOption{option.WithAPIKey(apiKey)}
+	if baseURL != "" {
+		opts = append(opts, option.WithBaseURL(baseURL))
+	}
+	return &anthropicProvider{client: anthropic.NewClient(opts...)}
+}
+
+func (p *anthropicProvider) Name() string {
+	return "anthropic"
+}
+
+func (p *anthropicProvider) params(req Request) anthropic.MessageNewParams {
+	model := req.Model
+	if model == "" {
+		model = defaultAnthropicModel
+	}
+
+	params := anthropic.MessageNewParams{
+		Model:     anthropic.Model(model),
+		MaxTokens: int64(req.MaxTokens),
+	}
+	if req.Temperature != nil {
+		params.Temperature = anthropic.Float(*req.Temperature)
+	}
+	if req.System != "" {
+		params.System = []anthropic.TextBlockParam{{Text: req.System, Type: "text"}}
+	}
+	for _, msg := range req.Messages {
+		role := anthropic.MessageParamRoleUser
+		if msg.Role == "assistant" {
+			role = anthropic.MessageParamRoleAssistant
+		}
+		params.Messages = append(params.Messages, anthropic.MessageParam{
+			Role:    role,
+			Content: []anthropic.ContentBlockParamUnion{{OfText: &anthropic.TextBlockParam{Text: msg.Content, Type: "text"}}},
+		})
+	}
+	return params
+}
+
+func (p *anthropicProvider) Complete(ctx context.Context, req Request) (string, error) {
+	params := p.params(req)
+	response, err := p.client.Messages.New(ctx, params)
+	if err != nil {
+		return "", withModel(anthropicError(err), string(params.Model))
+	}
+	if len(response.Content) == 0 {
+		return "", fmt.Errorf("no response content from Anthropic")
+	}
+	for _, content := range response.Content {
+		if content.Type == "text" && content.Text != "" {
+			return content.Text, nil
+		}
+	}
+	return "", fmt.Errorf("unexpected response format from Anthropic")
+}
+
+func (p *anthropicProvider) Stream(ctx context.Context, req Request, onText func(string)) (string, error) {
+	params := p.params(req)
+	stream := p.client.Messages.NewStreaming(ctx, params)
+	defer stream.Close()
+
+	var text strings.Builder
+	for stream.Next() {
+		event := stream.Current()
+		if event.Type == "content_block_delta" && event.Delta.Type == "text_delta" {
+			text.WriteString(event.Delta.Text)
+			onText(event.Delta.Text)
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return text.String(), withModel(anthropicError(err), string(params.Model))
+	}
+	return text.String(), nil
+}
+
+func (p *anthropicProvider) Models(ctx context.Context) ([]string, error) {
+	var models []string
+	pager := p.client.Models.ListAutoPaging(ctx, anthropic.ModelListParams{})
+	for pager.Next() {
+		models = append(models, pager.Current().ID)
+	}
+	if err := pager.Err(); err != nil {
+		return nil, anthropicError(err)
+	}
+	return models, nil
+}
+
+// Embed isn't offered by the Anthropic API, so semantic search falls back to
+// local vectors.
+func (p *anthropicProvider) Embed(ctx context.Context, model string, texts []string) ([][]float32, error) {
+	return nil, errNoEmbeddings
+}
+
+// anthropicError turns an API error into the message the API gave, rather
+// than the full request dump, and spots rejected models.
+func anthropicError(err error) error {
+	var apiErr *anthropic.Error
+	if !errors.As(err, &apiErr) {
+		return fmt.Errorf("Anthropic API error: %w", err)
+	}
+
+	var body struct {
+		Error struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal([]byte(apiErr.RawJSON()), &body) != nil || body.Error.Message == "" {
+		return fmt.Errorf("Anthropic API error: %w", err)
+	}
+
+	// The only thing the Messages API can't find is the model
+	if body.Error.Type == "not_found_error" {
+		return errModelNotFound
+	}
+	return fmt.Errorf("Anthropic API error: %s (%d)", body.Error.Message, apiErr.StatusCode)
+}
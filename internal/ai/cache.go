@@ -0,0 +1,174 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// defaultCacheTTL is how long a reply is reused when AI_CACHE_TTL isn't set.
+const defaultCacheTTL = 12 * time.Hour
+
+// Caches, as folders in ~/river/.cache
+const (
+	replyCache   = "ai"
+	summaryCache = "summaries"
+)
+
+// cachedReply is a reply kept in ~/river/.cache so asking again about the
+// same notes doesn't pay again.
+type cachedReply struct {
+	Command string    `json:"command"`
+	Model   string    `json:"model,omitempty"`
+	Created time.Time `json:"created"`
+	Output  string    `json:"output"`
+}
+
+func cacheDir(cache string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, "river", ".cache", cache), nil
+}
+
+// cacheKey identifies a request by everything that shapes the reply: the
+// command and its model settings, the template text, the notes and scope,
+// which tells apart requests about the same notes that differ otherwise.
+func cacheKey(settings CommandSettings, t Template, notes, scope string) string {
+	temperature := "default"
+	if settings.Temperature != nil {
+		temperature = fmt.Sprint(*settings.Temperature)
+	}
+
+	h := sha256.New()
+	for _, part := range []string{
+		settings.Command, LoadSettings().Provider, settings.Model,
+		fmt.Sprint(settings.MaxTokens), temperature, fmt.Sprint(settings.ContextTokens),
+		hashNotes(t.System + "\x00" + t.User), hashNotes(notes), scope,
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
+
+func loadCachedReply(cache, key string, ttl time.Duration) (cachedReply, bool) {
+	var reply cachedReply
+	dir, err := cacheDir(cache)
+	if err != nil {
+		return reply, false
+	}
+	data, err := os.ReadFile(filepath.Join(dir, key+".json"))
+	if err != nil || json.Unmarshal(data, &reply) != nil {
+		return reply, false
+	}
+	if time.Since(reply.Created) > ttl || reply.Output == "" {
+		return reply, false
+	}
+	return reply, true
+}
+
+// saveCachedReply stores reply and clears out replies that have expired.
+func saveCachedReply(cache, key string, reply cachedReply, ttl time.Duration) error {
+	dir, err := cacheDir(cache)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	if entries, err := os.ReadDir(dir); err == nil {
+		for _, entry := range entries {
+			if info, err := entry.Info(); err == nil && time.Since(info.ModTime()) > ttl {
+				os.Remove(filepath.Join(dir, entry.Name()))
+			}
+		}
+	}
+
+	data, err := json.MarshalIndent(reply, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, key+".json"), data, 0644)
+}
+
+// ask fills in the command's template and sends it, reusing a cached reply to
+// the same request unless settings ask for a fresh one. A cached reply is
+// written to out whole, and its time is returned; it's zero for a new reply.
+// Notes too long to send whole are summarized first. out may be nil.
+func ask(ctx context.Context, settings CommandSettings, data TemplateData, scope string, out *printer) (string, time.Time, error) {
+	var onText func(string)
+	if out != nil {
+		onText = out.write
+	}
+
+	t, err := LoadTemplate(settings.Command)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	system, user, err := t.Render(data)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	useCache := settings.CacheTTL > 0
+	key := cacheKey(settings, t, data.Notes, scope)
+	if useCache && !settings.Fresh {
+		if reply, ok := loadCachedReply(replyCache, key, settings.CacheTTL); ok {
+			if onText != nil {
+				onText(reply.Output)
+			}
+			return reply.Output, reply.Created, nil
+		}
+	}
+
+	if estimateTokens(data.Notes) > settings.ContextTokens && len(data.days) > 1 {
+		data.Notes, err = summarizeNotes(ctx, settings, data.days, out)
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		if system, user, err = t.Render(data); err != nil {
+			return "", time.Time{}, err
+		}
+		out.status(waitingLabel(settings))
+	}
+
+	text, err := complete(ctx, settings, system, user, onText)
+	if err != nil || ctx.Err() != nil {
+		return text, time.Time{}, err
+	}
+	if useCache {
+		// Failing to cache only means paying again next time
+		saveCachedReply(replyCache, key, cachedReply{
+			Command: settings.Command,
+			Model:   settings.Model,
+			Created: time.Now(),
+			Output:  text,
+		}, settings.CacheTTL)
+	}
+	return text, time.Time{}, nil
+}
+
+// forgetReply drops the cached reply to a request, for replies that turned out
+// to be unusable.
+func forgetReply(settings CommandSettings, data TemplateData, scope string) {
+	t, err := LoadTemplate(settings.Command)
+	dir, dirErr := cacheDir(replyCache)
+	if err != nil || dirErr != nil {
+		return
+	}
+	os.Remove(filepath.Join(dir, cacheKey(settings, t, data.Notes, scope)+".json"))
+}
+
+// printCacheNotice says a reply was reused, and how to get a new one.
+func printCacheNotice(cachedAt time.Time) {
+	fmt.Printf("\n♻️  Reused the reply from %s, since your notes haven't changed. Add --fresh to ask again.\n",
+		cachedAt.Format("Jan 2 15:04"))
+}
@@ -0,0 +1,188 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// chatPassages is how many passages each question retrieves.
+	chatPassages = 8
+	// chatContext is how many passages the conversation keeps in view, so
+	// follow-up questions can still refer to earlier answers.
+	chatContext = 24
+)
+
+// citationPattern matches a date cited in an answer, e.g. [2025-03-14].
+var citationPattern = regexp.MustCompile(`\[(\d{4}-\d{2}-\d{2})\]`)
+
+// Chat is a conversation about the journal. Each question retrieves the
+// passages most relevant to it, and answers cite them by date.
+type Chat struct {
+	settings CommandSettings
+	notes    []Passage // Every paragraph in range, searched for each question
+	history  []Message
+	context  []Passage // Retrieved so far, oldest first
+}
+
+// NewChat starts a conversation about the notes in opts' window, or the
+// whole journal when it has no dates.
+func NewChat(opts Options) (*Chat, error) {
+	if opts.Journal != "" {
+		return nil, fmt.Errorf("river chat reads ~/river/notes, so that citations open in the editor; --journal isn't supported")
+	}
+	settings, err := LoadCommandSettings("chat", opts)
+	if err != nil {
+		return nil, err
+	}
+	r, err := opts.Window.resolveAll()
+	if err != nil {
+		return nil, err
+	}
+	days, err := readNotes(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading notes: %v", err)
+	}
+	return &Chat{settings: settings, notes: passages(days)}, nil
+}
+
+// Entries is how many paragraphs the chat searches.
+func (c *Chat) Entries() int {
+	return len(c.notes)
+}
+
+// Model names the model answering, empty for the provider's default.
+func (c *Chat) Model() string {
+	return c.settings.Model
+}
+
+// Answer is the reply to one question.
+type Answer struct {
+	Text      string
+	Citations []time.Time // Dates cited, in the order they first appear
+}
+
+// retrieve finds the passages for question. The previous question is
+// searched too, so a follow-up like "and before that?" stays on topic. With
+// no matches, the newest passages give the model something to go on.
+func (c *Chat) retrieve(question string) []Passage {
+	query := question
+	for i := len(c.history) - 1; i >= 0; i-- {
+		if c.history[i].Role == "user" {
+			query += "\n" + c.history[i].Content
+			break
+		}
+	}
+
+	found := searchKeywords(c.notes, question, chatPassages)
+	if len(found) < chatPassages {
+		for _, p := range searchKeywords(c.notes, query, chatPassages) {
+			if len(found) < chatPassages && !containsPassage(found, p) {
+				found = append(found, p)
+			}
+		}
+	}
+	if len(found) == 0 {
+		// Notes are read newest first
+		found = c.notes[:min(chatPassages, len(c.notes))]
+	}
+	return found
+}
+
+func containsPassage(ps []Passage, p Passage) bool {
+	for _, q := range ps {
+		if q.Date.Equal(p.Date) && q.Text == p.Text {
+			return true
+		}
+	}
+	return false
+}
+
+// formatPassages lists ps by date, newest first, under headings that give
+// the date in the form answers cite.
+func formatPassages(ps []Passage) string {
+	sorted := append([]Passage(nil), ps...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date.After(sorted[j].Date)
+	})
+
+	var b strings.Builder
+	for i, p := range sorted {
+		if i == 0 || !p.Date.Equal(sorted[i-1].Date) {
+			fmt.Fprintf(&b, "\n=== [%s] %s ===\n", p.Date.Format("2006-01-02"), p.Date.Format("Monday, January 2, 2006"))
+		}
+		b.WriteString(p.Text + "\n")
+	}
+	return b.String()
+}
+
+// Ask answers question in the context of the conversation so far, streaming
+// the answer to onText. An interrupted answer isn't added to the
+// conversation.
+func (c *Chat) Ask(ctx context.Context, question string, onText func(string)) (Answer, error) {
+	inView := c.context
+	for _, p := range c.retrieve(question) {
+		if !containsPassage(inView, p) {
+			inView = append(inView, p)
+		}
+	}
+	inView = inView[max(0, len(inView)-chatContext):]
+
+	t, err := LoadTemplate("chat")
+	if err != nil {
+		return Answer{}, err
+	}
+	data := TemplateData{
+		Notes:    formatPassages(inView),
+		Today:    time.Now().Format("Monday, January 2, 2006"),
+		Question: question,
+	}
+	system, user, err := t.Render(data)
+	if err != nil {
+		return Answer{}, err
+	}
+
+	messages := append(append([]Message(nil), c.history...), Message{Role: "user", Content: user})
+	text, err := converse(ctx, c.settings, system, messages, onText)
+	if err != nil {
+		return Answer{Text: text}, err
+	}
+	if ctx.Err() != nil {
+		return Answer{Text: text}, ctx.Err()
+	}
+
+	c.context = inView
+	c.history = append(messages, Message{Role: "assistant", Content: text})
+	return Answer{Text: text, Citations: citations(text)}, nil
+}
+
+// citations returns the dates cited in text that have an entry.
+func citations(text string) []time.Time {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+
+	var dates []time.Time
+	seen := make(map[string]bool)
+	for _, match := range citationPattern.FindAllStringSubmatch(text, -1) {
+		if seen[match[1]] {
+			continue
+		}
+		seen[match[1]] = true
+		date, err := time.ParseInLocation("2006-01-02", match[1], time.Local)
+		if err != nil {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(homeDir, "river", "notes", match[1]+".md")); err == nil {
+			dates = append(dates, date)
+		}
+	}
+	return dates
+}
@@ -0,0 +1,186 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeChatServer streams numbered answers and keeps the messages of every
+// request. A question containing "stop" gets one chunk and then waits to be
+// cancelled.
+func fakeChatServer(t *testing.T) func() [][]openAIMessage {
+	t.Helper()
+	var mu sync.Mutex
+	var requests [][]openAIMessage
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body openAIRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		requests = append(requests, body.Messages)
+		n := len(requests)
+		mu.Unlock()
+
+		chunk := func(text string) {
+			data, _ := json.Marshal(map[string]any{
+				"choices": []map[string]any{{"delta": map[string]string{"content": text}}},
+			})
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			w.(http.Flusher).Flush()
+		}
+		if strings.Contains(body.Messages[len(body.Messages)-1].Content, "stop") {
+			chunk("Partial")
+			select {
+			case <-r.Context().Done():
+			case <-release:
+			}
+			return
+		}
+		chunk("answer ")
+		chunk(fmt.Sprint(n))
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	config := "AI_PROVIDER=ollama\nAI_BASE_URL=" + server.URL + "\nAI_MODEL=test\n"
+	if err := os.MkdirAll(filepath.Join(home, "river"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, "river", ".config"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return func() [][]openAIMessage {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([][]openAIMessage(nil), requests...)
+	}
+}
+
+// chatAbout returns a chat whose notes have chatPassages paragraphs about each
+// topic, so every question about one retrieves a fresh set of passages.
+func chatAbout(topics ...string) *Chat {
+	var notes []Passage
+	for i, topic := range topics {
+		date := time.Date(2026, 3, 1+i, 0, 0, 0, 0, time.Local)
+		for j := range chatPassages {
+			notes = append(notes, Passage{Date: date, Text: fmt.Sprintf("%s visit %d", topic, j)})
+		}
+	}
+	return &Chat{settings: CommandSettings{Command: "chat", Model: "test"}, notes: notes}
+}
+
+func roles(messages []openAIMessage) string {
+	var out []string
+	for _, m := range messages {
+		out = append(out, m.Role)
+	}
+	return strings.Join(out, " ")
+}
+
+func TestChatAskKeepsHistory(t *testing.T) {
+	requests := fakeChatServer(t)
+	c := chatAbout("harbor", "violin")
+
+	for _, question := range []string{"harbor?", "violin?"} {
+		if _, err := c.Ask(context.Background(), question, func(string) {}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sent := requests()
+	if len(sent) != 2 {
+		t.Fatalf("sent %d requests, want 2", len(sent))
+	}
+	if got, want := roles(sent[1]), "system user assistant user"; got != want {
+		t.Fatalf("second request has roles %q, want %q", got, want)
+	}
+	if sent[1][1].Content != "harbor?" || sent[1][2].Content != "answer 1" || sent[1][3].Content != "violin?" {
+		t.Errorf("second request carries %+v, want the first question and answer before the second question", sent[1][1:])
+	}
+	if len(c.history) != 4 {
+		t.Errorf("history has %d messages, want 4", len(c.history))
+	}
+}
+
+func TestChatAskTrimsContext(t *testing.T) {
+	requests := fakeChatServer(t)
+	topics := []string{"harbor", "violin", "garden", "meteor"}
+	c := chatAbout(topics...)
+
+	for _, topic := range topics {
+		if _, err := c.Ask(context.Background(), topic+"?", func(string) {}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(c.context) != chatContext {
+		t.Fatalf("%d passages in view, want %d", len(c.context), chatContext)
+	}
+	for _, p := range c.context {
+		if strings.HasPrefix(p.Text, "harbor") {
+			t.Fatalf("oldest passage %q is still in view", p.Text)
+		}
+	}
+
+	sent := requests()
+	system := sent[len(sent)-1][0].Content
+	if strings.Contains(system, "harbor") || !strings.Contains(system, "meteor") {
+		t.Errorf("last prompt should have dropped the oldest topic and kept the newest:\n%s", system)
+	}
+	if got := len(sent[len(sent)-1]); got != 2*len(topics) {
+		t.Errorf("last request has %d messages, want every earlier turn kept", got)
+	}
+}
+
+func TestChatAskInterrupted(t *testing.T) {
+	requests := fakeChatServer(t)
+	c := chatAbout("harbor", "violin")
+
+	if _, err := c.Ask(context.Background(), "harbor?", func(string) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Cancel as soon as the answer starts streaming
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	answer, err := c.Ask(ctx, "violin, then stop?", func(string) { cancel() })
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("interrupted answer failed with %v, want context.Canceled", err)
+	}
+	if answer.Text != "Partial" {
+		t.Errorf("interrupted answer = %q, want the text so far", answer.Text)
+	}
+	if len(c.history) != 2 {
+		t.Errorf("history has %d messages after an interrupted answer, want 2", len(c.history))
+	}
+	for _, p := range c.context {
+		if strings.HasPrefix(p.Text, "violin") {
+			t.Fatalf("passage %q retrieved for the interrupted question stayed in view", p.Text)
+		}
+	}
+
+	if _, err := c.Ask(context.Background(), "garden?", func(string) {}); err != nil {
+		t.Fatal(err)
+	}
+	sent := requests()
+	if got, want := roles(sent[len(sent)-1]), "system user assistant user"; got != want {
+		t.Errorf("request after the interruption has roles %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,185 @@
+package ai
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattwhite/river-go/internal/config"
+)
+
+// Options override a command's settings for one run, from command line flags.
+// Zero values keep the configured settings.
+type Options struct {
+	Model       string
+	MaxTokens   *int // Nil for the configured length
+	Temperature *float64
+	Fresh       bool // Ask again rather than reuse a cached reply
+	Window           // Which notes to read
+}
+
+// CommandSettings are the model parameters one AI command runs with.
+type CommandSettings struct {
+	Command       string
+	Model         string // Empty for the provider's default
+	MaxTokens     int
+	Temperature   *float64 // Nil for the provider's default
+	Fresh         bool
+	CacheTTL      time.Duration // How long replies are reused; 0 turns caching off
+	ContextTokens int           // Most notes to send at once before summarizing them
+}
+
+// commandDefaults are the Anthropic settings each command was tuned for.
+var commandDefaults = map[string]CommandSettings{
+	"think":     {Model: "claude-sonnet-4-20250514", MaxTokens: 1000},
+	"analyze":   {Model: "claude-haiku-4-5", MaxTokens: 1200},
+	"todo":      {Model: "claude-haiku-4-5", MaxTokens: 800},
+	"prompts":   {Model: "claude-haiku-4-5", MaxTokens: 800},
+	"insights":  {Model: "claude-haiku-4-5", MaxTokens: 1500},
+	"summarize": {Model: "claude-haiku-4-5", MaxTokens: 600},
+	"chat":      {Model: "claude-haiku-4-5", MaxTokens: 1000},
+}
+
+// defaultContextTokens is how much of the notes is sent at once when
+// AI_CONTEXT_TOKENS isn't set. Local servers often run models with small
+// context windows, so they get much less.
+var defaultContextTokens = map[string]int{
+	"anthropic": 40000,
+	"openai":    40000,
+}
+
+const defaultLocalContextTokens = 6000
+
+// maxTokensLimit is the most any command may ask for.
+const maxTokensLimit = 64000
+
+// LoadCommandSettings works out the settings for command, from most to least
+// specific: opts, then AI_<COMMAND>_MODEL, _MAX_TOKENS and _TEMPERATURE in
+// ~/river/.config, then AI_MODEL, AI_MAX_TOKENS and AI_TEMPERATURE, then the
+// command's defaults. AI_CACHE_TTL sets how long replies are reused and
+// AI_CONTEXT_TOKENS how much of the notes to send before summarizing them.
+// Invalid values are reported rather than ignored.
+func LoadCommandSettings(command string, opts Options) (CommandSettings, error) {
+	s, ok := commandDefaults[command]
+	if !ok {
+		return s, fmt.Errorf("unknown AI command %q", command)
+	}
+	s.Command = command
+
+	values := config.Load()
+	provider := LoadSettings().Provider
+	prefix := "AI_" + strings.ToUpper(command) + "_"
+
+	// The defaults name Anthropic models, which other providers won't have
+	if provider != "anthropic" {
+		s.Model = ""
+	}
+	for _, key := range []string{"AI_MODEL", prefix + "MODEL"} {
+		if model := values.String(key, ""); model != "" {
+			s.Model = model
+		}
+	}
+	if opts.Model != "" {
+		s.Model = opts.Model
+	}
+	if strings.ContainsAny(s.Model, " \t") {
+		return s, fmt.Errorf("invalid model %q: model ids don't contain spaces", s.Model)
+	}
+
+	for _, key := range []string{"AI_MAX_TOKENS", prefix + "MAX_TOKENS"} {
+		if value := values.String(key, ""); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return s, fmt.Errorf("%s in ~/river/.config: %q is not a whole number", key, value)
+			}
+			if err := checkMaxTokens(n); err != nil {
+				return s, fmt.Errorf("%s in ~/river/.config: %v", key, err)
+			}
+			s.MaxTokens = n
+		}
+	}
+	if opts.MaxTokens != nil {
+		if err := checkMaxTokens(*opts.MaxTokens); err != nil {
+			return s, fmt.Errorf("--max-tokens: %v", err)
+		}
+		s.MaxTokens = *opts.MaxTokens
+	}
+
+	for _, key := range []string{"AI_TEMPERATURE", prefix + "TEMPERATURE"} {
+		if value := values.String(key, ""); value != "" {
+			t, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return s, fmt.Errorf("%s in ~/river/.config: %q is not a number", key, value)
+			}
+			if err := checkTemperature(t, provider); err != nil {
+				return s, fmt.Errorf("%s in ~/river/.config: %v", key, err)
+			}
+			s.Temperature = &t
+		}
+	}
+	if opts.Temperature != nil {
+		if err := checkTemperature(*opts.Temperature, provider); err != nil {
+			return s, fmt.Errorf("--temperature: %v", err)
+		}
+		s.Temperature = opts.Temperature
+	}
+
+	s.Fresh = opts.Fresh
+	s.CacheTTL = defaultCacheTTL
+	if value := values.String("AI_CACHE_TTL", ""); value != "" {
+		ttl, err := time.ParseDuration(value)
+		if err != nil || ttl < 0 {
+			return s, fmt.Errorf("AI_CACHE_TTL in ~/river/.config: %q is not a duration like 12h or 30m", value)
+		}
+		s.CacheTTL = ttl
+	}
+
+	s.ContextTokens = defaultLocalContextTokens
+	if n, ok := defaultContextTokens[provider]; ok {
+		s.ContextTokens = n
+	}
+	if value := values.String("AI_CONTEXT_TOKENS", ""); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1000 {
+			return s, fmt.Errorf("AI_CONTEXT_TOKENS in ~/river/.config: %q should be a number of tokens, at least 1000", value)
+		}
+		s.ContextTokens = n
+	}
+
+	return s, nil
+}
+
+func checkMaxTokens(n int) error {
+	if n < 1 || n > maxTokensLimit {
+		return fmt.Errorf("max tokens must be between 1 and %d, got %d", maxTokensLimit, n)
+	}
+	return nil
+}
+
+// checkTemperature allows 0-1 for Anthropic and 0-2 for OpenAI-compatible
+// servers, matching what their APIs accept.
+func checkTemperature(t float64, provider string) error {
+	limit := 2.0
+	if provider == "anthropic" {
+		limit = 1.0
+	}
+	if math.IsNaN(t) || t < 0 || t > limit {
+		return fmt.Errorf("temperature must be between 0 and %g for %s, got %g", limit, provider, t)
+	}
+	return nil
+}
+
+// ModelError is returned when the provider rejects the model id.
+type ModelError struct {
+	Provider string
+	Command  string
+	Model    string
+}
+
+func (e *ModelError) Error() string {
+	return fmt.Sprintf("%s doesn't recognize the model %q. Run 'river ai models' to see the ones available, "+
+		"then pass --model or set AI_%s_MODEL in ~/river/.config",
+		e.Provider, e.Model, strings.ToUpper(e.Command))
+}
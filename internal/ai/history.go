@@ -0,0 +1,237 @@
+package ai
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Run is one AI command's result, kept in ~/river/ai/ so past runs can be
+// compared.
+type Run struct {
+	ID        string    `json:"id"`
+	Command   string    `json:"command"`
+	Created   time.Time `json:"created"`
+	Start     string    `json:"start"` // First day of notes, 2006-01-02
+	End       string    `json:"end"`
+	Provider  string    `json:"provider"`
+	Model     string    `json:"model,omitempty"` // Empty for the provider's default
+	NotesHash string    `json:"notes_hash"`
+	Output    string    `json:"output"`
+}
+
+func historyDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, "river", "ai"), nil
+}
+
+// hashNotes identifies the notes a run was given without storing them again.
+func hashNotes(notes string) string {
+	sum := sha256.Sum256([]byte(notes))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// newRun describes a run of command over the notes in r.
+func newRun(command string, settings CommandSettings, notes string, r noteRange, output string) Run {
+	now := time.Now()
+	return Run{
+		ID:        now.Format("20060102-150405") + "-" + command,
+		Command:   command,
+		Created:   now,
+		Start:     r.Start.Format("2006-01-02"),
+		End:       r.End.Format("2006-01-02"),
+		Provider:  LoadSettings().Provider,
+		Model:     settings.Model,
+		NotesHash: hashNotes(notes),
+		Output:    output,
+	}
+}
+
+// saveRun adds run to the history. When another run of the same command in
+// the same second took its ID, a numeric suffix tells them apart.
+func saveRun(run Run) error {
+	dir, err := historyDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	base := run.ID
+	for n := 2; ; n++ {
+		data, err := json.MarshalIndent(run, "", "  ")
+		if err != nil {
+			return err
+		}
+		f, err := os.OpenFile(filepath.Join(dir, run.ID+".json"), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if os.IsExist(err) {
+			run.ID = fmt.Sprintf("%s-%d", base, n)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(data); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+}
+
+// recordRun saves a CLI command's run, warning rather than failing when it
+// can't.
+func recordRun(command string, settings CommandSettings, notes string, r noteRange, output string) {
+	if err := saveRun(newRun(command, settings, notes, r, output)); err != nil {
+		fmt.Printf("\n⚠️  Could not save this run to the history: %v\n", err)
+	}
+}
+
+// LoadHistory returns every saved run, newest first. Files that can't be read
+// are skipped.
+func LoadHistory() ([]Run, error) {
+	dir, err := historyDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []Run
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		var run Run
+		if json.Unmarshal(data, &run) != nil {
+			continue
+		}
+		runs = append(runs, run)
+	}
+
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].Created.After(runs[j].Created)
+	})
+	return runs, nil
+}
+
+// FindRun looks a run up in runs by ID, of which a unique prefix is enough,
+// or by its number in the list (1 is the newest). IDs start with digits, so a
+// plain number is only taken as a position when no ID starts with it; "#3"
+// always means the third run.
+func FindRun(runs []Run, ref string) (Run, error) {
+	if n, ok := strings.CutPrefix(ref, "#"); ok {
+		return runAt(runs, n)
+	}
+
+	var matches []Run
+	for _, run := range runs {
+		if run.ID == ref {
+			return run, nil
+		}
+		if strings.HasPrefix(run.ID, ref) {
+			matches = append(matches, run)
+		}
+	}
+	_, numErr := strconv.Atoi(ref)
+	switch {
+	case len(matches) == 1:
+		return matches[0], nil
+	case len(matches) == 0 && numErr == nil:
+		return runAt(runs, ref)
+	case len(matches) == 0:
+		return Run{}, fmt.Errorf("no run matches %q", ref)
+	case numErr == nil:
+		return Run{}, fmt.Errorf("%q matches %d runs; use more of the ID, or #%s for run %s in the list", ref, len(matches), ref, ref)
+	default:
+		return Run{}, fmt.Errorf("%q matches %d runs; use more of the ID", ref, len(matches))
+	}
+}
+
+// runAt returns the run at position n in the list, counting from 1.
+func runAt(runs []Run, n string) (Run, error) {
+	i, err := strconv.Atoi(n)
+	if err != nil {
+		return Run{}, fmt.Errorf("%q is not a run number", "#"+n)
+	}
+	if i < 1 || i > len(runs) {
+		return Run{}, fmt.Errorf("there is no run %d; the history has %d", i, len(runs))
+	}
+	return runs[i-1], nil
+}
+
+// PreviousRun returns the run of the same command before run, if any.
+func PreviousRun(runs []Run, run Run) (Run, bool) {
+	for _, r := range runs {
+		if r.Command == run.Command && r.Created.Before(run.Created) {
+			return r, true
+		}
+	}
+	return Run{}, false
+}
+
+// DiffLines compares two texts line by line. Each line of the result starts
+// with "+ " (only in b), "- " (only in a) or "  " (in both).
+func DiffLines(a, b string) []string {
+	x := strings.Split(strings.TrimRight(a, "\n"), "\n")
+	y := strings.Split(strings.TrimRight(b, "\n"), "\n")
+
+	// lcs[i][j] is the longest common subsequence of x[i:] and y[j:]
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out []string
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			out = append(out, "  "+x[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, "- "+x[i])
+			i++
+		default:
+			out = append(out, "+ "+y[j])
+			j++
+		}
+	}
+	for ; i < len(x); i++ {
+		out = append(out, "- "+x[i])
+	}
+	for ; j < len(y); j++ {
+		out = append(out, "+ "+y[j])
+	}
+	return out
+}
@@ -0,0 +1,154 @@
+package ai
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []string
+	}{
+		{
+			name: "identical",
+			a:    "one\ntwo\n",
+			b:    "one\ntwo",
+			want: []string{"  one", "  two"},
+		},
+		{
+			name: "line added",
+			a:    "one\nthree",
+			b:    "one\ntwo\nthree",
+			want: []string{"  one", "+ two", "  three"},
+		},
+		{
+			name: "line removed",
+			a:    "one\ntwo\nthree",
+			b:    "one\nthree",
+			want: []string{"  one", "- two", "  three"},
+		},
+		{
+			name: "line changed",
+			a:    "one\ntwo\nthree",
+			b:    "one\n2\nthree",
+			want: []string{"  one", "- two", "+ 2", "  three"},
+		},
+		{
+			name: "added at the end",
+			a:    "one",
+			b:    "one\ntwo\nthree",
+			want: []string{"  one", "+ two", "+ three"},
+		},
+		{
+			name: "removed at the start",
+			a:    "zero\none",
+			b:    "one",
+			want: []string{"- zero", "  one"},
+		},
+		{
+			name: "keeps the longest common lines",
+			a:    "a\nb\nc\nd",
+			b:    "b\nc\nd\na",
+			want: []string{"- a", "  b", "  c", "  d", "+ a"},
+		},
+		{
+			name: "nothing in common",
+			a:    "old",
+			b:    "new",
+			want: []string{"- old", "+ new"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DiffLines(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffLines = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSaveRunKeepsSameSecondApart(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	created := time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC)
+	for _, output := range []string{"first", "second", "third"} {
+		run := Run{ID: "20260304-090000-todo", Command: "todo", Created: created, Output: output}
+		if err := saveRun(run); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runs, err := LoadHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputs := make(map[string]string)
+	for _, run := range runs {
+		outputs[run.ID] = run.Output
+	}
+	want := map[string]string{
+		"20260304-090000-todo":   "first",
+		"20260304-090000-todo-2": "second",
+		"20260304-090000-todo-3": "third",
+	}
+	if !reflect.DeepEqual(outputs, want) {
+		t.Errorf("saved runs = %v, want %v", outputs, want)
+	}
+
+	if run, err := FindRun(runs, "20260304-090000-todo"); err != nil || run.Output != "first" {
+		t.Errorf("FindRun by full ID = %q, %v, want the first run", run.Output, err)
+	}
+}
+
+func TestFindRun(t *testing.T) {
+	runs := []Run{
+		{ID: "20260305-101500-todo"},
+		{ID: "20260304-090000-think"},
+		{ID: "20260304-090000-todo"},
+		{ID: "20250101-080000-todo"},
+	}
+
+	tests := []struct {
+		ref  string
+		want string // ID of the run found
+		err  string // Part of the error, when one is expected
+	}{
+		{ref: "1", want: "20260305-101500-todo"},
+		{ref: "#4", want: "20250101-080000-todo"},
+		{ref: "2025", want: "20250101-080000-todo"},
+		{ref: "20260305", want: "20260305-101500-todo"},
+		{ref: "20260304-090000-todo", want: "20260304-090000-todo"},
+		{ref: "20260304-090000-th", want: "20260304-090000-think"},
+		{ref: "2026", err: `"2026" matches 3 runs; use more of the ID, or #2026`},
+		{ref: "20260304-090000-t", err: "matches 2 runs; use more of the ID"},
+		{ref: "5", err: "there is no run 5; the history has 4"},
+		{ref: "#0", err: "there is no run 0"},
+		{ref: "#2026", err: "there is no run 2026"},
+		{ref: "#two", err: "not a run number"},
+		{ref: "2024", err: "there is no run 2024"},
+		{ref: "todo", err: `no run matches "todo"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			run, err := FindRun(runs, tt.ref)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("FindRun error = %v, want one containing %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if run.ID != tt.want {
+				t.Errorf("FindRun = %s, want %s", run.ID, tt.want)
+			}
+		})
+	}
+}
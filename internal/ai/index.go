@@ -0,0 +1,457 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// indexVersion is bumped whenever the index format changes, forcing a
+	// rebuild.
+	indexVersion = 1
+	// hashBuckets is how many dimensions words are hashed into for the local
+	// TF-IDF vectors.
+	hashBuckets = 1 << 16
+	// embedBatch is how many paragraphs are sent to the embeddings endpoint
+	// at once.
+	embedBatch = 64
+)
+
+// indexedParagraph is one paragraph of a note with its vectors.
+type indexedParagraph struct {
+	Hash      string
+	Text      string
+	Terms     map[uint32]float32 // Stemmed word counts, hashed into hashBuckets
+	Embedding []float32          // Unit length; nil until embedded
+}
+
+// indexedFile is the index of one note, kept until its size or modification
+// time changes.
+type indexedFile struct {
+	Date       time.Time
+	Size       int64
+	ModTime    time.Time
+	Paragraphs []indexedParagraph
+}
+
+// vectorIndex holds a vector for every paragraph in ~/river/notes, so semantic
+// search only has to embed what changed since the last one.
+type vectorIndex struct {
+	Version int
+	Model   string                 // Embeddings model the vectors came from
+	Files   map[string]indexedFile // Keyed by path
+}
+
+func indexPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, "river", ".cache", "index.gob"), nil
+}
+
+func readIndex() vectorIndex {
+	empty := vectorIndex{Version: indexVersion, Files: make(map[string]indexedFile)}
+
+	path, err := indexPath()
+	if err != nil {
+		return empty
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return empty
+	}
+	defer f.Close()
+
+	var ix vectorIndex
+	if err := gob.NewDecoder(f).Decode(&ix); err != nil || ix.Version != indexVersion || ix.Files == nil {
+		return empty
+	}
+	return ix
+}
+
+func writeIndex(ix vectorIndex) error {
+	path, err := indexPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	// Write to a temp file first so a concurrent reader never sees half an index
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if err := gob.NewEncoder(f).Encode(ix); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// noteDate returns the date a note is for, from its file name.
+func noteDate(path string) (time.Time, bool) {
+	base := filepath.Base(path)
+	if filepath.Ext(base) != ".md" || strings.HasPrefix(base, ".") {
+		return time.Time{}, false
+	}
+	date, err := time.ParseInLocation("2006-01-02", strings.TrimSuffix(base, ".md"), time.Local)
+	return date, err == nil
+}
+
+// hashTerms counts the stemmed words in text, hashing each into one of
+// hashBuckets dimensions so vectors stay a fixed size however large the
+// vocabulary grows.
+func hashTerms(text string) map[uint32]float32 {
+	counts := make(map[uint32]float32)
+	for word, n := range terms(text) {
+		h := fnv.New32a()
+		h.Write([]byte(word))
+		counts[h.Sum32()%hashBuckets] += float32(n)
+	}
+	return counts
+}
+
+// indexNote reads the paragraphs of the note at path. Paragraphs that were
+// already in old keep their embeddings.
+func indexNote(path string, date time.Time, info os.FileInfo, old indexedFile) (indexedFile, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return indexedFile{}, err
+	}
+
+	embeddings := make(map[string][]float32)
+	for _, p := range old.Paragraphs {
+		if p.Embedding != nil {
+			embeddings[p.Hash] = p.Embedding
+		}
+	}
+
+	f := indexedFile{Date: date, Size: info.Size(), ModTime: info.ModTime()}
+	for _, text := range splitParagraphs(string(content), nil) {
+		sum := sha256.Sum256([]byte(text))
+		hash := hex.EncodeToString(sum[:])
+		f.Paragraphs = append(f.Paragraphs, indexedParagraph{
+			Hash:      hash,
+			Text:      text,
+			Terms:     hashTerms(text),
+			Embedding: embeddings[hash],
+		})
+	}
+	return f, nil
+}
+
+// refresh brings ix up to date with the notes in dir. Only files whose size or
+// modification time changed are read again. It reports whether anything did.
+func (ix *vectorIndex) refresh(dir string) (bool, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.md"))
+	if err != nil {
+		return false, err
+	}
+
+	fresh := make(map[string]indexedFile, len(files))
+	changed := false
+	for _, file := range files {
+		date, ok := noteDate(file)
+		if !ok {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+
+		old, ok := ix.Files[file]
+		if ok && old.Size == info.Size() && old.ModTime.Equal(info.ModTime()) {
+			fresh[file] = old
+			continue
+		}
+		entry, err := indexNote(file, date, info, old)
+		if err != nil {
+			continue
+		}
+		fresh[file] = entry
+		changed = true
+	}
+
+	// Deleted notes drop out of the index too
+	if len(fresh) != len(ix.Files) {
+		changed = true
+	}
+	ix.Files = fresh
+	return changed, nil
+}
+
+// useModel drops the embeddings when they came from a different model, since
+// vectors from two models can't be compared. It reports whether it did.
+func (ix *vectorIndex) useModel(model string) bool {
+	if ix.Model == model {
+		return false
+	}
+	ix.Model = model
+	for _, f := range ix.Files {
+		for i := range f.Paragraphs {
+			f.Paragraphs[i].Embedding = nil
+		}
+	}
+	return true
+}
+
+// embedMissing embeds the paragraphs that don't have a vector yet, in batches,
+// reporting how many are done to progress. Batches finished before an error
+// are kept.
+func (ix *vectorIndex) embedMissing(ctx context.Context, provider Provider, progress func(done, total int)) (int, error) {
+	var missing []*indexedParagraph
+	for _, f := range ix.Files {
+		for i := range f.Paragraphs {
+			if f.Paragraphs[i].Embedding == nil {
+				missing = append(missing, &f.Paragraphs[i])
+			}
+		}
+	}
+
+	done := 0
+	for done < len(missing) {
+		batch := missing[done:min(done+embedBatch, len(missing))]
+		texts := make([]string, len(batch))
+		for i, p := range batch {
+			texts[i] = p.Text
+		}
+		vectors, err := provider.Embed(ctx, ix.Model, texts)
+		if err != nil {
+			return done, err
+		}
+		for i, v := range vectors {
+			batch[i].Embedding = normalize(v)
+		}
+		done += len(batch)
+		progress(done, len(missing))
+	}
+	return done, nil
+}
+
+// normalize scales v to unit length, so cosine similarity is a dot product.
+func normalize(v []float32) []float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	out := make([]float32, len(v))
+	if sum == 0 {
+		return out
+	}
+	norm := math.Sqrt(sum)
+	for i, x := range v {
+		out[i] = float32(float64(x) / norm)
+	}
+	return out
+}
+
+func dot(a, b []float32) float64 {
+	var sum float64
+	for i := range min(len(a), len(b)) {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
+
+// UpdateIndex refreshes the index entry for one note, typically right after
+// the editor saved it. Its new paragraphs are embedded by the next search, so
+// saving never waits on the network. Without an index yet there's nothing to
+// update; the first search builds it.
+func UpdateIndex(path string) error {
+	ixPath, err := indexPath()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(ixPath); err != nil {
+		return nil
+	}
+	date, ok := noteDate(path)
+	if !ok {
+		return nil
+	}
+
+	ix := readIndex()
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		delete(ix.Files, path)
+		return writeIndex(ix)
+	}
+	if err != nil {
+		return err
+	}
+	entry, err := indexNote(path, date, info, ix.Files[path])
+	if err != nil {
+		return err
+	}
+	ix.Files[path] = entry
+	return writeIndex(ix)
+}
+
+// SearchSemantic returns the paragraphs in w closest in meaning to query, best
+// first, along with how they were compared. Paragraphs are embedded through
+// the provider when AI_EMBED_MODEL names a model; otherwise, or when the
+// provider can't embed, local TF-IDF vectors are used. Progress messages are
+// sent to progress.
+func SearchSemantic(ctx context.Context, query string, w Window, limit int, progress func(string)) ([]Passage, string, error) {
+	if w.Journal != "" {
+		return nil, "", fmt.Errorf("the search index covers ~/river/notes; --journal isn't supported with --semantic")
+	}
+	r, err := w.resolveAll()
+	if err != nil {
+		return nil, "", err
+	}
+
+	ix := readIndex()
+	changed, err := ix.refresh(r.Dir)
+	if err != nil {
+		return nil, "", err
+	}
+
+	settings := LoadSettings()
+	var queryVector []float32
+	method := "local TF-IDF vectors (set AI_EMBED_MODEL to use an embeddings model)"
+	if model := settings.EmbedModel; model != "" {
+		if ix.useModel(model) {
+			changed = true
+		}
+		vector, embedded, err := embedIndex(ctx, settings, &ix, query, progress)
+		if err != nil && ctx.Err() == nil {
+			method = fmt.Sprintf("local TF-IDF vectors (%s)", err)
+		} else if err == nil {
+			queryVector = vector
+			method = "embeddings from " + model
+		}
+		// Embeddings finished before a failure are worth keeping
+		if embedded > 0 {
+			changed = true
+		}
+	}
+	if changed {
+		// An index that can't be written only costs speed next time
+		writeIndex(ix)
+	}
+	if ctx.Err() != nil {
+		return nil, "", ctx.Err()
+	}
+
+	var tag *regexp.Regexp
+	if r.Tag != "" {
+		tag = tagPattern(r.Tag)
+	}
+	var candidates []indexedParagraph
+	var dates []time.Time
+	for _, f := range ix.Files {
+		if f.Date.Before(r.Start) || f.Date.After(r.End) {
+			continue
+		}
+		for _, p := range f.Paragraphs {
+			if tag == nil || tag.MatchString(p.Text) {
+				candidates = append(candidates, p)
+				dates = append(dates, f.Date)
+			}
+		}
+	}
+
+	var ranked []Passage
+	if queryVector != nil {
+		for i, p := range candidates {
+			ranked = append(ranked, Passage{Date: dates[i], Text: p.Text, Score: dot(queryVector, p.Embedding)})
+		}
+	} else {
+		scores := tfidfScores(candidates, query)
+		for i, p := range candidates {
+			if scores[i] > 0 {
+				ranked = append(ranked, Passage{Date: dates[i], Text: p.Text, Score: scores[i]})
+			}
+		}
+	}
+	sortPassages(ranked)
+	return ranked[:min(limit, len(ranked))], method, nil
+}
+
+// embedIndex embeds the paragraphs missing from ix and then query, returning
+// the query's vector and how many paragraphs were embedded.
+func embedIndex(ctx context.Context, settings Settings, ix *vectorIndex, query string, progress func(string)) ([]float32, int, error) {
+	provider, err := NewProvider(settings)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	embedded, err := ix.embedMissing(ctx, provider, func(done, total int) {
+		progress(fmt.Sprintf("Embedding paragraphs %d of %d...", done, total))
+	})
+	if err == nil {
+		var vectors [][]float32
+		vectors, err = provider.Embed(ctx, ix.Model, []string{query})
+		if err == nil {
+			return normalize(vectors[0]), embedded, nil
+		}
+	}
+
+	switch {
+	case errors.Is(err, errNoEmbeddings):
+		err = fmt.Errorf("%s has no embeddings endpoint", provider.Name())
+	case errors.Is(err, errModelNotFound):
+		err = fmt.Errorf("%s has no embeddings model %q", provider.Name(), ix.Model)
+	}
+	return nil, embedded, err
+}
+
+// tfidfScores compares query with each of ps by the cosine of their TF-IDF
+// vectors, weighting words by how rare they are among ps.
+func tfidfScores(ps []indexedParagraph, query string) []float64 {
+	df := make(map[uint32]int)
+	for _, p := range ps {
+		for bucket := range p.Terms {
+			df[bucket]++
+		}
+	}
+	idf := func(bucket uint32) float64 {
+		return math.Log(float64(1+len(ps))/float64(1+df[bucket])) + 1
+	}
+
+	queryTerms := hashTerms(query)
+	var queryNorm float64
+	for bucket, n := range queryTerms {
+		weight := float64(n) * idf(bucket)
+		queryNorm += weight * weight
+	}
+
+	scores := make([]float64, len(ps))
+	if queryNorm == 0 {
+		return scores
+	}
+	for i, p := range ps {
+		var product, norm float64
+		for bucket, n := range p.Terms {
+			weight := float64(n) * idf(bucket)
+			norm += weight * weight
+			if q, ok := queryTerms[bucket]; ok {
+				product += weight * float64(q) * idf(bucket)
+			}
+		}
+		if norm > 0 {
+			scores[i] = product / math.Sqrt(norm*queryNorm)
+		}
+	}
+	return scores
+}
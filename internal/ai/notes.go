@@ -0,0 +1,222 @@
+package ai
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Window picks the notes an AI command reads, from command line flags. Zero
+// values keep the command's usual window of recent days.
+type Window struct {
+	Days    int
+	Since   time.Time // First day, inclusive
+	Until   time.Time // Last day, inclusive; defaults to today
+	Tag     string    // Only paragraphs with this #tag
+	Journal string    // Directory of notes to read instead of ~/river/notes
+}
+
+// noteRange is a Window worked out into the days and directory to read.
+type noteRange struct {
+	Start, End time.Time
+	Dir        string
+	Tag        string
+}
+
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// resolve works out the range w covers. defaultDays is the command's usual
+// window, used when w doesn't say how far back to go.
+func (w Window) resolve(defaultDays int) (noteRange, error) {
+	if w.Days < 0 {
+		return noteRange{}, fmt.Errorf("--days must be at least 1, got %d", w.Days)
+	}
+	if w.Days > 0 && !w.Since.IsZero() {
+		return noteRange{}, fmt.Errorf("use --days or --since, not both")
+	}
+
+	r := noteRange{
+		End: day(time.Now()),
+		Tag: strings.TrimPrefix(strings.TrimSpace(w.Tag), "#"),
+	}
+	if !w.Until.IsZero() {
+		r.End = day(w.Until)
+	}
+
+	switch {
+	case !w.Since.IsZero():
+		r.Start = day(w.Since)
+	case w.Days > 0:
+		r.Start = r.End.AddDate(0, 0, -(w.Days - 1))
+	default:
+		r.Start = r.End.AddDate(0, 0, -(defaultDays - 1))
+	}
+	if r.Start.After(r.End) {
+		return noteRange{}, fmt.Errorf("--since %s is after --until %s",
+			r.Start.Format("2006-01-02"), r.End.Format("2006-01-02"))
+	}
+
+	dir, err := journalDir(w.Journal)
+	if err != nil {
+		return noteRange{}, err
+	}
+	r.Dir = dir
+	return r, nil
+}
+
+// journalDir finds the notes directory for --journal, which may be a path or
+// the name of a folder in ~/river. Without one it's ~/river/notes.
+func journalDir(journal string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	if journal == "" {
+		return filepath.Join(homeDir, "river", "notes"), nil
+	}
+
+	candidates := []string{journal}
+	if rest, ok := strings.CutPrefix(journal, "~/"); ok {
+		candidates = []string{filepath.Join(homeDir, rest)}
+	} else if !strings.ContainsRune(journal, filepath.Separator) {
+		candidates = append(candidates, filepath.Join(homeDir, "river", journal))
+	}
+	for _, dir := range candidates {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir, nil
+		}
+	}
+	return "", fmt.Errorf("--journal: no directory called %q", journal)
+}
+
+// resolveAll is like resolve, except that without --days or --since the range
+// starts at the first entry rather than a fixed number of days back.
+func (w Window) resolveAll() (noteRange, error) {
+	r, err := w.resolve(1)
+	if err != nil || w.Days > 0 || !w.Since.IsZero() {
+		return r, err
+	}
+
+	entries, err := os.ReadDir(r.Dir)
+	if err != nil && !os.IsNotExist(err) {
+		return r, err
+	}
+	for _, entry := range entries {
+		date, err := time.ParseInLocation("2006-01-02", strings.TrimSuffix(entry.Name(), ".md"), time.Local)
+		if err == nil && filepath.Ext(entry.Name()) == ".md" && date.Before(r.Start) {
+			r.Start = date
+		}
+	}
+	return r, nil
+}
+
+// days is how many days the range covers.
+func (r noteRange) days() int {
+	return int(r.End.Sub(r.Start).Hours()/24+0.5) + 1
+}
+
+// describe names the range for progress messages, e.g. "the last 10 days" or
+// "Jan 1, 2025 to Mar 31, 2025, tagged #work".
+func (r noteRange) describe() string {
+	s := fmt.Sprintf("the last %d days", r.days())
+	if !r.End.Equal(day(time.Now())) {
+		s = r.Start.Format("Jan 2, 2006") + " to " + r.End.Format("Jan 2, 2006")
+	}
+	if r.Tag != "" {
+		s += ", tagged #" + r.Tag
+	}
+	return s
+}
+
+// scope tells apart cached replies about the same notes read differently.
+func (r noteRange) scope() string {
+	return fmt.Sprintf("%s %s %s #%s", r.Start.Format("2006-01-02"), r.End.Format("2006-01-02"), r.Dir, r.Tag)
+}
+
+// tagPattern matches tag as a whole #word, in any case.
+func tagPattern(tag string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)(^|[^\w&#])#` + regexp.QuoteMeta(tag) + `($|[^\w-])`)
+}
+
+// dayNotes is one day's entry, without comments and blank lines.
+type dayNotes struct {
+	Date       time.Time
+	Paragraphs []string
+}
+
+func (d dayNotes) Text() string {
+	return strings.Join(d.Paragraphs, "\n")
+}
+
+// readNotes reads the notes in r, newest first. With a tag, only the
+// paragraphs mentioning it are kept.
+func readNotes(r noteRange) ([]dayNotes, error) {
+	var tag *regexp.Regexp
+	if r.Tag != "" {
+		tag = tagPattern(r.Tag)
+	}
+
+	var days []dayNotes
+
+	for date := r.End; !date.Before(r.Start); date = date.AddDate(0, 0, -1) {
+		dateStr := date.Format("2006-01-02")
+		filename := filepath.Join(r.Dir, dateStr+".md")
+
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		if paragraphs := splitParagraphs(string(content), tag); len(paragraphs) > 0 {
+			days = append(days, dayNotes{Date: date, Paragraphs: paragraphs})
+		}
+	}
+
+	return days, nil
+}
+
+// splitParagraphs returns the paragraphs of an entry, without its comment
+// lines. With a tag, only the paragraphs mentioning it are kept.
+func splitParagraphs(content string, tag *regexp.Regexp) []string {
+	var paragraphs, paragraph []string
+	keepParagraph := func() {
+		text := strings.Join(paragraph, "\n")
+		if len(paragraph) > 0 && (tag == nil || tag.MatchString(text)) {
+			paragraphs = append(paragraphs, text)
+		}
+		paragraph = nil
+	}
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "<!--") && strings.HasSuffix(trimmed, "-->") {
+			continue
+		}
+		if trimmed == "" {
+			keepParagraph()
+			continue
+		}
+		paragraph = append(paragraph, line)
+	}
+	keepParagraph()
+	return paragraphs
+}
+
+// formatNotes joins days into the text templates get, each day under a
+// "=== date ===" heading.
+func formatNotes(days []dayNotes) string {
+	var allContent strings.Builder
+	for _, d := range days {
+		allContent.WriteString(fmt.Sprintf("\n=== %s ===\n", d.Date.Format("Monday, January 2, 2006")))
+		allContent.WriteString(d.Text())
+		allContent.WriteString("\n")
+	}
+	return allContent.String()
+}
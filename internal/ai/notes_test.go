@@ -0,0 +1,133 @@
+package ai
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWindowResolve(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	for _, dir := range []string{"river/notes", "river/work", "elsewhere"} {
+		if err := os.MkdirAll(filepath.Join(home, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	date := func(day int) time.Time { return time.Date(2026, 3, day, 0, 0, 0, 0, time.Local) }
+	today := day(time.Now())
+
+	tests := []struct {
+		name       string
+		window     Window
+		start, end time.Time
+		dir        string // Relative to the home directory
+		tag        string
+		err        string // Part of the error, when one is expected
+	}{
+		{
+			name:  "usual window",
+			start: today.AddDate(0, 0, -9),
+			end:   today,
+			dir:   "river/notes",
+		},
+		{
+			name:   "usual window before until",
+			window: Window{Until: date(10).Add(15 * time.Hour)},
+			start:  date(1),
+			end:    date(10),
+			dir:    "river/notes",
+		},
+		{
+			name:   "days",
+			window: Window{Days: 3, Until: date(10)},
+			start:  date(8),
+			end:    date(10),
+			dir:    "river/notes",
+		},
+		{
+			name:   "single day",
+			window: Window{Since: date(4), Until: date(4)},
+			start:  date(4),
+			end:    date(4),
+			dir:    "river/notes",
+		},
+		{
+			name:   "since",
+			window: Window{Since: date(2).Add(9 * time.Hour), Until: date(20)},
+			start:  date(2),
+			end:    date(20),
+			dir:    "river/notes",
+		},
+		{
+			name:   "tag",
+			window: Window{Days: 1, Until: date(10), Tag: " #work "},
+			start:  date(10),
+			end:    date(10),
+			dir:    "river/notes",
+			tag:    "work",
+		},
+		{
+			name:   "journal in river",
+			window: Window{Days: 1, Until: date(10), Journal: "work"},
+			start:  date(10),
+			end:    date(10),
+			dir:    "river/work",
+		},
+		{
+			name:   "journal from home",
+			window: Window{Days: 1, Until: date(10), Journal: "~/elsewhere"},
+			start:  date(10),
+			end:    date(10),
+			dir:    "elsewhere",
+		},
+		{
+			name:   "missing journal",
+			window: Window{Journal: "nowhere"},
+			err:    `no directory called "nowhere"`,
+		},
+		{
+			name:   "negative days",
+			window: Window{Days: -1},
+			err:    "at least 1",
+		},
+		{
+			name:   "days and since",
+			window: Window{Days: 3, Since: date(1)},
+			err:    "not both",
+		},
+		{
+			name:   "since after until",
+			window: Window{Since: date(11), Until: date(10)},
+			err:    "--since 2026-03-11 is after --until 2026-03-10",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := tt.window.resolve(10)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("resolve error = %v, want one containing %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !r.Start.Equal(tt.start) || !r.End.Equal(tt.end) {
+				t.Errorf("range = %s to %s, want %s to %s", r.Start.Format("2006-01-02"), r.End.Format("2006-01-02"),
+					tt.start.Format("2006-01-02"), tt.end.Format("2006-01-02"))
+			}
+			if want := filepath.Join(home, tt.dir); r.Dir != want {
+				t.Errorf("dir = %s, want %s", r.Dir, want)
+			}
+			if r.Tag != tt.tag {
+				t.Errorf("tag = %q, want %q", r.Tag, tt.tag)
+			}
+		})
+	}
+}
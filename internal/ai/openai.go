@@ -0,0 +1,261 @@
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// defaultOpenAIModel is used with OpenAI itself when a request doesn't name a
+// model.
+const defaultOpenAIModel = "gpt-4o-mini"
+
+// openAIProvider talks to any server with the OpenAI chat completions API:
+// OpenAI itself, Ollama, the llama.cpp server and LM Studio.
+type openAIProvider struct {
+	name    string
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+func newOpenAIProvider(name, baseURL, apiKey string) *openAIProvider {
+	return &openAIProvider{
+		name:    name,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		client:  http.DefaultClient,
+	}
+}
+
+func (p *openAIProvider) Name() string {
+	return p.name
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIRequest struct {
+	Model       string          `json:"model"`
+	Messages    []openAIMessage `json:"messages"`
+	MaxTokens   int             `json:"max_tokens,omitempty"`
+	Temperature *float64        `json:"temperature,omitempty"`
+	Stream      bool            `json:"stream,omitempty"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message openAIMessage `json:"message"`
+		Delta   openAIMessage `json:"delta"`
+	} `json:"choices"`
+}
+
+type openAIError struct {
+	Error struct {
+		Message string `json:"message"`
+		Code    any    `json:"code"` // A string from OpenAI, a number from some local servers
+	} `json:"error"`
+}
+
+// do sends a request to path and returns the response once it succeeds.
+func (p *openAIProvider) do(ctx context.Context, method, path string, body any) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, p.baseURL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("%s error: %w (is the server running at %s?)", p.name, err, p.baseURL)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		var apiErr openAIError
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error.Message != "" {
+			// OpenAI says model_not_found; local servers only answer a POST to
+			// an endpoint they have with 404 when the model isn't loaded
+			if apiErr.Error.Code == "model_not_found" ||
+				(method == http.MethodPost && resp.StatusCode == http.StatusNotFound) {
+				return nil, errModelNotFound
+			}
+			return nil, fmt.Errorf("%s error: %s", p.name, apiErr.Error.Message)
+		}
+		return nil, fmt.Errorf("%s error: %s", p.name, resp.Status)
+	}
+	return resp, nil
+}
+
+// request builds the body for req. Without a model, OpenAI gets
+// defaultOpenAIModel and a local server the one model it offers; with several
+// to choose from, AI_MODEL has to pick.
+func (p *openAIProvider) request(ctx context.Context, req Request, stream bool) (openAIRequest, error) {
+	body := openAIRequest{Model: req.Model, MaxTokens: req.MaxTokens, Temperature: req.Temperature, Stream: stream}
+	if body.Model == "" && p.name == "openai" {
+		body.Model = defaultOpenAIModel
+	}
+	if body.Model == "" {
+		models, err := p.Models(ctx)
+		if err != nil {
+			return body, err
+		}
+		switch len(models) {
+		case 0:
+			return body, fmt.Errorf("%s has no models available; set AI_MODEL in ~/river/.config", p.name)
+		case 1:
+			body.Model = models[0]
+		default:
+			return body, fmt.Errorf("%s offers %d models; set AI_MODEL in ~/river/.config to pick one (see 'river ai models')",
+				p.name, len(models))
+		}
+	}
+
+	if req.System != "" {
+		body.Messages = append(body.Messages, openAIMessage{Role: "system", Content: req.System})
+	}
+	for _, msg := range req.Messages {
+		body.Messages = append(body.Messages, openAIMessage{Role: msg.Role, Content: msg.Content})
+	}
+	return body, nil
+}
+
+func (p *openAIProvider) Complete(ctx context.Context, req Request) (string, error) {
+	body, err := p.request(ctx, req, false)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := p.do(ctx, http.MethodPost, "/chat/completions", body)
+	if err != nil {
+		return "", withModel(err, body.Model)
+	}
+	defer resp.Body.Close()
+
+	var out openAIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("%s error: %w", p.name, err)
+	}
+	if len(out.Choices) == 0 || out.Choices[0].Message.Content == "" {
+		return "", fmt.Errorf("no response content from %s", p.name)
+	}
+	return out.Choices[0].Message.Content, nil
+}
+
+func (p *openAIProvider) Stream(ctx context.Context, req Request, onText func(string)) (string, error) {
+	body, err := p.request(ctx, req, true)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := p.do(ctx, http.MethodPost, "/chat/completions", body)
+	if err != nil {
+		return "", withModel(err, body.Model)
+	}
+	defer resp.Body.Close()
+
+	// Server-sent events: one "data: {...}" line per chunk, then "data: [DONE]"
+	var text strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk openAIResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			continue
+		}
+		if len(chunk.Choices) > 0 && chunk.Choices[0].Delta.Content != "" {
+			text.WriteString(chunk.Choices[0].Delta.Content)
+			onText(chunk.Choices[0].Delta.Content)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return text.String(), fmt.Errorf("%s error: %w", p.name, err)
+	}
+	return text.String(), nil
+}
+
+func (p *openAIProvider) Models(ctx context.Context) ([]string, error) {
+	resp, err := p.do(ctx, http.MethodGet, "/models", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var out struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("%s error: %w", p.name, err)
+	}
+
+	models := make([]string, 0, len(out.Data))
+	for _, model := range out.Data {
+		models = append(models, model.ID)
+	}
+	return models, nil
+}
+
+func (p *openAIProvider) Embed(ctx context.Context, model string, texts []string) ([][]float32, error) {
+	body := struct {
+		Model string   `json:"model"`
+		Input []string `json:"input"`
+	}{Model: model, Input: texts}
+
+	resp, err := p.do(ctx, http.MethodPost, "/embeddings", body)
+	if err != nil {
+		return nil, withModel(err, model)
+	}
+	defer resp.Body.Close()
+
+	var out struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("%s error: %w", p.name, err)
+	}
+	if len(out.Data) != len(texts) {
+		return nil, fmt.Errorf("%s returned %d embeddings for %d texts", p.name, len(out.Data), len(texts))
+	}
+
+	vectors := make([][]float32, len(texts))
+	for i, d := range out.Data {
+		if d.Index >= 0 && d.Index < len(vectors) {
+			i = d.Index
+		}
+		vectors[i] = d.Embedding
+	}
+	return vectors, nil
+}
@@ -0,0 +1,191 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mattwhite/river-go/internal/config"
+	"github.com/mattwhite/river-go/internal/onboarding"
+)
+
+// errNoAPIKey is returned when a provider that needs a key doesn't have one.
+// The CLI commands offer onboarding when they see it.
+var errNoAPIKey = errors.New("No API key found. Run 'river onboard' to set up AI features")
+
+// errModelNotFound is returned by providers that reject the requested model.
+var errModelNotFound = errors.New("model not found")
+
+// unknownModelError is errModelNotFound naming the model that was sent, which
+// may be a default the provider picked rather than one the user asked for.
+type unknownModelError struct {
+	model string
+}
+
+func (e *unknownModelError) Error() string {
+	return fmt.Sprintf("model %q not found", e.model)
+}
+
+func (e *unknownModelError) Unwrap() error {
+	return errModelNotFound
+}
+
+// withModel names model in err when it's errModelNotFound.
+func withModel(err error, model string) error {
+	if errors.Is(err, errModelNotFound) {
+		return &unknownModelError{model: model}
+	}
+	return err
+}
+
+// errNoEmbeddings is returned by providers without an embeddings endpoint.
+var errNoEmbeddings = errors.New("embeddings aren't available")
+
+// Message is one turn of a conversation.
+type Message struct {
+	Role    string // "user" or "assistant"
+	Content string
+}
+
+// Request is a single completion request.
+type Request struct {
+	Model       string // Empty for the provider's default
+	System      string
+	Messages    []Message
+	MaxTokens   int
+	Temperature *float64 // Nil for the provider's default
+}
+
+// Provider is a language model backend.
+type Provider interface {
+	// Name identifies the provider in messages.
+	Name() string
+
+	// Complete returns the model's reply to req.
+	Complete(ctx context.Context, req Request) (string, error)
+
+	// Stream calls onText with each piece of the reply as it arrives, and
+	// returns the whole reply.
+	Stream(ctx context.Context, req Request, onText func(string)) (string, error)
+
+	// Models lists the models the provider can use.
+	Models(ctx context.Context) ([]string, error)
+
+	// Embed returns a vector for each of texts, from model. Providers
+	// without embeddings return errNoEmbeddings.
+	Embed(ctx context.Context, model string, texts []string) ([][]float32, error)
+}
+
+// Settings configures the provider, read from ~/river/.config:
+//
+//	AI_PROVIDER=anthropic      anthropic (default), openai, ollama, llamacpp or lmstudio
+//	AI_BASE_URL=...            Server address for OpenAI-compatible providers
+//	AI_API_KEY=...             Key for OpenAI-compatible providers, if needed
+//	AI_MODEL=...               Model to use instead of the defaults
+//	AI_EMBED_MODEL=...         Embeddings model for semantic search
+type Settings struct {
+	Provider   string
+	BaseURL    string
+	APIKey     string
+	Model      string
+	EmbedModel string // Empty when semantic search falls back to TF-IDF
+}
+
+// Base URLs of local servers when AI_BASE_URL isn't set.
+var defaultBaseURLs = map[string]string{
+	"openai":   "https://api.openai.com/v1",
+	"ollama":   "http://localhost:11434/v1",
+	"llamacpp": "http://localhost:8080/v1",
+	"lmstudio": "http://localhost:1234/v1",
+}
+
+// LoadSettings reads the provider settings from the config file.
+func LoadSettings() Settings {
+	values := config.Load()
+	s := Settings{
+		Provider: strings.ToLower(values.String("AI_PROVIDER", "anthropic")),
+		BaseURL:  values.String("AI_BASE_URL", ""),
+		APIKey:   values.String("AI_API_KEY", ""),
+		Model:    values.String("AI_MODEL", ""),
+	}
+	if s.Provider == "anthropic" {
+		s.APIKey = onboarding.LoadAPIKey()
+	}
+
+	// Local servers only have embeddings with a model loaded for them
+	embedModel := ""
+	if s.Provider == "openai" {
+		embedModel = "text-embedding-3-small"
+	}
+	s.EmbedModel = values.String("AI_EMBED_MODEL", embedModel)
+	if strings.EqualFold(s.EmbedModel, "none") {
+		s.EmbedModel = ""
+	}
+	return s
+}
+
+// NewProvider returns the provider described by s.
+func NewProvider(s Settings) (Provider, error) {
+	switch s.Provider {
+	case "anthropic", "":
+		if s.APIKey == "" {
+			return nil, errNoAPIKey
+		}
+		return newAnthropicProvider(s.APIKey, s.BaseURL), nil
+	}
+
+	baseURL, ok := defaultBaseURLs[s.Provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown AI_PROVIDER %q, expected anthropic, openai, ollama, llamacpp or lmstudio", s.Provider)
+	}
+	if s.BaseURL != "" {
+		baseURL = s.BaseURL
+	}
+	if s.Provider == "openai" && s.APIKey == "" {
+		return nil, fmt.Errorf("AI_PROVIDER=openai needs AI_API_KEY in ~/river/.config")
+	}
+	return newOpenAIProvider(s.Provider, baseURL, s.APIKey), nil
+}
+
+// complete sends one prompt to the configured provider with the command's
+// settings. With onText the reply is streamed to it as it arrives.
+func complete(ctx context.Context, settings CommandSettings, system, prompt string, onText func(string)) (string, error) {
+	return converse(ctx, settings, system, []Message{{Role: "user", Content: prompt}}, onText)
+}
+
+// converse is complete for a conversation, ending with the user's turn.
+func converse(ctx context.Context, settings CommandSettings, system string, messages []Message, onText func(string)) (string, error) {
+	provider, err := NewProvider(LoadSettings())
+	if err != nil {
+		return "", err
+	}
+
+	req := Request{
+		Model:       settings.Model,
+		System:      system,
+		Messages:    messages,
+		MaxTokens:   settings.MaxTokens,
+		Temperature: settings.Temperature,
+	}
+	var text string
+	if onText != nil {
+		text, err = provider.Stream(ctx, req, onText)
+	} else {
+		text, err = provider.Complete(ctx, req)
+	}
+	var unknown *unknownModelError
+	if errors.As(err, &unknown) {
+		return "", &ModelError{Provider: provider.Name(), Command: settings.Command, Model: unknown.model}
+	}
+	return text, err
+}
+
+// ListModels returns the models available from the configured provider.
+func ListModels() ([]string, error) {
+	provider, err := NewProvider(LoadSettings())
+	if err != nil {
+		return nil, err
+	}
+	return provider.Models(context.Background())
+}
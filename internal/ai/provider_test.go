@@ -0,0 +1,64 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProviderErrorsKeepCancellation(t *testing.T) {
+	// The server holds every request until the test is over
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+	provider := newOpenAIProvider("ollama", server.URL, "")
+
+	tests := []struct {
+		name string
+		call func(ctx context.Context) error
+	}{
+		{"complete", func(ctx context.Context) error {
+			_, err := provider.Complete(ctx, Request{Model: "test", Messages: []Message{{Role: "user", Content: "hi"}}})
+			return err
+		}},
+		{"stream", func(ctx context.Context) error {
+			_, err := provider.Stream(ctx, Request{Model: "test", Messages: []Message{{Role: "user", Content: "hi"}}}, func(string) {})
+			return err
+		}},
+		{"models", func(ctx context.Context) error {
+			_, err := provider.Models(ctx)
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			go func() {
+				<-started
+				cancel()
+			}()
+			if err := tt.call(ctx); !errors.Is(err, context.Canceled) {
+				t.Errorf("error = %v, want one wrapping context.Canceled", err)
+			}
+		})
+	}
+}
+
+func TestAnthropicErrorKeepsCancellation(t *testing.T) {
+	err := anthropicError(fmt.Errorf("post: %w", context.Canceled))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("anthropicError = %v, want one wrapping context.Canceled", err)
+	}
+}
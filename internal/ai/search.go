@@ -0,0 +1,125 @@
+package ai
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattwhite/river-go/internal/language"
+)
+
+// Passage is a paragraph of an entry, with how well it matched a search.
+type Passage struct {
+	Date  time.Time
+	Text  string
+	Score float64
+}
+
+// passages splits days into their paragraphs.
+func passages(days []dayNotes) []Passage {
+	var out []Passage
+	for _, d := range days {
+		for _, paragraph := range d.Paragraphs {
+			out = append(out, Passage{Date: d.Date, Text: paragraph})
+		}
+	}
+	return out
+}
+
+// stem trims common endings so "walking", "walked" and "walks" match.
+func stem(word string) string {
+	if strings.HasSuffix(word, "ss") {
+		return word
+	}
+	for _, suffix := range []string{"ing", "ed", "s"} {
+		if strings.HasSuffix(word, suffix) && len(word)-len(suffix) >= 3 {
+			return strings.TrimSuffix(word, suffix)
+		}
+	}
+	return word
+}
+
+// terms returns the stemmed counts of the words in text worth searching for.
+func terms(text string) map[string]int {
+	counts := make(map[string]int)
+	for word, n := range language.Analyze(text).Terms {
+		counts[stem(word)] += n
+	}
+	return counts
+}
+
+// searchKeywords ranks all against query with BM25, returning at most limit
+// passages that share a word with it, best first.
+func searchKeywords(all []Passage, query string, limit int) []Passage {
+	const k1, b = 1.2, 0.75
+
+	queryTerms := terms(query)
+	if len(queryTerms) == 0 || len(all) == 0 {
+		return nil
+	}
+
+	docs := make([]map[string]int, len(all))
+	df := make(map[string]int)
+	totalLength := 0
+	for i, p := range all {
+		docs[i] = terms(p.Text)
+		for word := range docs[i] {
+			df[word]++
+		}
+		for _, n := range docs[i] {
+			totalLength += n
+		}
+	}
+	avgLength := math.Max(float64(totalLength)/float64(len(all)), 1)
+
+	var ranked []Passage
+	for i, p := range all {
+		length := 0
+		for _, n := range docs[i] {
+			length += n
+		}
+
+		score := 0.0
+		for word := range queryTerms {
+			tf := float64(docs[i][word])
+			if tf == 0 {
+				continue
+			}
+			idf := math.Log(1 + (float64(len(all))-float64(df[word])+0.5)/(float64(df[word])+0.5))
+			score += idf * tf * (k1 + 1) / (tf + k1*(1-b+b*float64(length)/avgLength))
+		}
+		if score > 0 {
+			p.Score = score
+			ranked = append(ranked, p)
+		}
+	}
+
+	sortPassages(ranked)
+	return ranked[:min(limit, len(ranked))]
+}
+
+// sortPassages puts the best matches first, and newer entries first among
+// equal ones.
+func sortPassages(ps []Passage) {
+	sort.SliceStable(ps, func(i, j int) bool {
+		if ps[i].Score != ps[j].Score {
+			return ps[i].Score > ps[j].Score
+		}
+		return ps[i].Date.After(ps[j].Date)
+	})
+}
+
+// SearchNotes returns the paragraphs in w that best match query, best first.
+// Without a date range in w, every entry is searched.
+func SearchNotes(query string, w Window, limit int) ([]Passage, error) {
+	r, err := w.resolveAll()
+	if err != nil {
+		return nil, err
+	}
+	days, err := readNotes(r)
+	if err != nil {
+		return nil, err
+	}
+	return searchKeywords(passages(days), query, limit), nil
+}
@@ -0,0 +1,147 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattwhite/river-go/internal/onboarding"
+)
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// printer writes a reply to the terminal as it streams in. Until the first
+// piece arrives it shows a spinner, which is skipped when output isn't a
+// terminal so pipes and files get only the reply.
+type printer struct {
+	heading string
+
+	labelMu sync.Mutex
+	label   string // Shown next to the spinner
+
+	mu      sync.Mutex
+	started bool   // The heading has been printed
+	last    string // End of what has been printed
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// newPrinter starts the spinner. heading is printed before the reply.
+func newPrinter(heading string, settings CommandSettings) *printer {
+	p := &printer{heading: heading, label: waitingLabel(settings), done: make(chan struct{})}
+
+	if isTerminal(os.Stdout) {
+		p.wg.Add(1)
+		go p.spin()
+	}
+	return p
+}
+
+func waitingLabel(settings CommandSettings) string {
+	if settings.Model != "" {
+		return fmt.Sprintf("Waiting for %s...", settings.Model)
+	}
+	return "Waiting for a reply..."
+}
+
+func (p *printer) spin() {
+	defer p.wg.Done()
+	ticker := time.NewTicker(80 * time.Millisecond)
+	defer ticker.Stop()
+
+	for frame := 0; ; frame++ {
+		p.labelMu.Lock()
+		fmt.Printf("\r%s %s\033[K", spinnerFrames[frame%len(spinnerFrames)], p.label)
+		p.labelMu.Unlock()
+		select {
+		case <-p.done:
+			fmt.Print("\r\033[K")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// stopSpinner clears the spinner. It's safe to call more than once.
+func (p *printer) stopSpinner() {
+	select {
+	case <-p.done:
+	default:
+		close(p.done)
+	}
+	p.wg.Wait()
+}
+
+// status changes what the spinner says is happening. p may be nil.
+func (p *printer) status(label string) {
+	if p == nil {
+		return
+	}
+	p.labelMu.Lock()
+	p.label = label
+	p.labelMu.Unlock()
+}
+
+// write prints the next piece of the reply.
+func (p *printer) write(text string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.started {
+		p.stopSpinner()
+		fmt.Print(p.heading)
+		p.started = true
+	}
+	fmt.Print(text)
+	if text != "" {
+		p.last = text
+	}
+}
+
+// finish stops the spinner once the request is over and turns its error into
+// the one to report. Interrupting with Ctrl+C isn't an error: whatever arrived
+// stays on screen.
+func (p *printer) finish(ctx context.Context, err error) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.stopSpinner()
+	if p.started && !strings.HasSuffix(p.last, "\n") {
+		fmt.Println()
+	}
+
+	if ctx.Err() != nil {
+		fmt.Println("\n⏹️  Stopped.")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error calling AI: %w", err)
+	}
+	return nil
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// retryAfterOnboarding runs request and, if it fails for want of an API key,
+// offers onboarding and runs it once more.
+func retryAfterOnboarding(request func() error) error {
+	err := request()
+	if !errors.Is(err, errNoAPIKey) {
+		return err
+	}
+
+	fmt.Print("\n🔑 API key not configured. Let's set it up now...\n\n")
+	if err := onboarding.RunOnboarding(); err != nil {
+		return fmt.Errorf("onboarding failed: %v", err)
+	}
+	// Try again after onboarding
+	fmt.Println("\n🔄 Retrying with your new API key...")
+	return request()
+}
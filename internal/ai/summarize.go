@@ -0,0 +1,151 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// summaryTTL is how long week summaries are kept. They only depend on the
+// notes, so a yearly review run again next month reuses most of them.
+const summaryTTL = 90 * 24 * time.Hour
+
+// estimateTokens guesses how many tokens text takes, at about four
+// characters each, which errs on the long side for most writing.
+func estimateTokens(text string) int {
+	return len(text)/4 + 1
+}
+
+// summaryPart is a summary standing in for the notes from Start to End.
+type summaryPart struct {
+	Start, End time.Time
+	Text       string
+}
+
+func formatSummaries(parts []summaryPart) string {
+	var b strings.Builder
+	for _, part := range parts {
+		fmt.Fprintf(&b, "\n=== Summary of %s to %s ===\n%s\n",
+			part.Start.Format("Monday, January 2, 2006"), part.End.Format("Monday, January 2, 2006"),
+			strings.TrimSpace(part.Text))
+	}
+	return b.String()
+}
+
+// weekStart returns the Monday of t's week.
+func weekStart(t time.Time) time.Time {
+	return day(t).AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+}
+
+// weekChunks splits days, newest first, into weeks. A week too long for
+// budget tokens is split further.
+func weekChunks(days []dayNotes, budget int) [][]dayNotes {
+	var chunks [][]dayNotes
+	var chunk []dayNotes
+	tokens := 0
+	for _, d := range days {
+		n := estimateTokens(formatNotes([]dayNotes{d}))
+		if len(chunk) > 0 && (!weekStart(d.Date).Equal(weekStart(chunk[0].Date)) || tokens+n > budget) {
+			chunks = append(chunks, chunk)
+			chunk, tokens = nil, 0
+		}
+		chunk = append(chunk, d)
+		tokens += n
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// summarizeNotes condenses days, newest first, to fit the command's context
+// budget: each week is summarized, then the summaries are combined in groups
+// until they fit. Summaries are cached by the notes they cover, so only weeks
+// that changed are summarized again. Progress is shown on out, which may be
+// nil.
+func summarizeNotes(ctx context.Context, settings CommandSettings, days []dayNotes, out *printer) (string, error) {
+	summarize, err := LoadCommandSettings("summarize", Options{})
+	if err != nil {
+		return "", err
+	}
+
+	chunks := weekChunks(days, settings.ContextTokens)
+	parts := make([]summaryPart, 0, len(chunks))
+	for i, chunk := range chunks {
+		out.status(fmt.Sprintf("Summarizing week %d of %d...", i+1, len(chunks)))
+		start, end := chunk[len(chunk)-1].Date, chunk[0].Date
+		text, err := summarizeText(ctx, summarize, formatNotes(chunk), start, end)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, summaryPart{Start: start, End: end, Text: text})
+	}
+
+	for {
+		text := formatSummaries(parts)
+		if estimateTokens(text) <= settings.ContextTokens || len(parts) == 1 {
+			return text, nil
+		}
+
+		// Combine neighboring summaries, at least two at a time so each
+		// round shrinks the list
+		out.status(fmt.Sprintf("Combining %d summaries...", len(parts)))
+		var combined []summaryPart
+		for i := 0; i < len(parts); {
+			j := i + 2
+			for j < len(parts) && estimateTokens(formatSummaries(parts[i:j+1])) <= settings.ContextTokens {
+				j++
+			}
+			j = min(j, len(parts))
+			if j-i == 1 {
+				combined = append(combined, parts[i])
+				break
+			}
+
+			group := parts[i:j]
+			start, end := group[len(group)-1].Start, group[0].End
+			text, err := summarizeText(ctx, summarize, formatSummaries(group), start, end)
+			if err != nil {
+				return "", err
+			}
+			combined = append(combined, summaryPart{Start: start, End: end, Text: text})
+			i = j
+		}
+		parts = combined
+	}
+}
+
+// summarizeText summarizes notes covering start to end, or returns the
+// cached summary of the same notes.
+func summarizeText(ctx context.Context, settings CommandSettings, notes string, start, end time.Time) (string, error) {
+	t, err := LoadTemplate(settings.Command)
+	if err != nil {
+		return "", err
+	}
+	system, user, err := t.Render(newTemplateText(notes, start, end))
+	if err != nil {
+		return "", err
+	}
+
+	key := cacheKey(settings, t, notes, start.Format("2006-01-02")+end.Format("2006-01-02"))
+	if reply, ok := loadCachedReply(summaryCache, key, summaryTTL); ok {
+		return reply.Output, nil
+	}
+
+	text, err := complete(ctx, settings, system, user, nil)
+	if err != nil {
+		return "", err
+	}
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	// Failing to cache only means summarizing again next time
+	saveCachedReply(summaryCache, key, cachedReply{
+		Command: settings.Command,
+		Model:   settings.Model,
+		Created: time.Now(),
+		Output:  text,
+	}, summaryTTL)
+	return text, nil
+}
@@ -0,0 +1,158 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// marchNotes returns notes for a day in March 2026, which starts on a Sunday.
+func marchNotes(day int, text string) dayNotes {
+	return dayNotes{Date: time.Date(2026, 3, day, 0, 0, 0, 0, time.Local), Paragraphs: []string{text}}
+}
+
+func TestWeekChunks(t *testing.T) {
+	long := strings.Repeat("x", 400)
+	n := estimateTokens(formatNotes([]dayNotes{marchNotes(4, long)}))
+
+	tests := []struct {
+		name   string
+		days   []dayNotes
+		budget int
+		want   [][]int // Days of March in each chunk
+	}{
+		{
+			name:   "no days",
+			budget: 1000,
+		},
+		{
+			name:   "one week",
+			days:   []dayNotes{marchNotes(8, "sun"), marchNotes(4, "wed"), marchNotes(2, "mon")},
+			budget: 1000,
+			want:   [][]int{{8, 4, 2}},
+		},
+		{
+			name:   "weeks start on Monday",
+			days:   []dayNotes{marchNotes(10, "tue"), marchNotes(9, "mon"), marchNotes(8, "sun"), marchNotes(1, "sun")},
+			budget: 1000,
+			want:   [][]int{{10, 9}, {8}, {1}},
+		},
+		{
+			name:   "long week is split",
+			days:   []dayNotes{marchNotes(6, long), marchNotes(5, long), marchNotes(4, long)},
+			budget: 2*n + n/2,
+			want:   [][]int{{6, 5}, {4}},
+		},
+		{
+			name:   "day over the budget stays whole",
+			days:   []dayNotes{marchNotes(5, long), marchNotes(4, "short")},
+			budget: n / 2,
+			want:   [][]int{{5}, {4}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][]int
+			for _, chunk := range weekChunks(tt.days, tt.budget) {
+				var days []int
+				for _, d := range chunk {
+					days = append(days, d.Date.Day())
+				}
+				got = append(got, days)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("weekChunks = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeSummarizer serves chat completions that number each summary, and
+// counts the requests it gets.
+func fakeSummarizer(t *testing.T) *atomic.Int32 {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/chat/completions") {
+			http.NotFound(w, r)
+			return
+		}
+		n := requests.Add(1)
+		json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]string{"content": fmt.Sprintf("summary %d", n)}}},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	config := "AI_PROVIDER=ollama\nAI_BASE_URL=" + server.URL + "\nAI_MODEL=test\n"
+	if err := os.MkdirAll(filepath.Join(home, "river"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, "river", ".config"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return &requests
+}
+
+func TestSummarizeNotes(t *testing.T) {
+	requests := fakeSummarizer(t)
+	days := []dayNotes{marchNotes(17, "third week"), marchNotes(10, "second week"), marchNotes(3, "first week")}
+
+	text, err := summarizeNotes(context.Background(), CommandSettings{ContextTokens: 10000}, days, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("summarized with %d requests, want one per week", got)
+	}
+	want := formatSummaries([]summaryPart{
+		{Start: days[0].Date, End: days[0].Date, Text: "summary 1"},
+		{Start: days[1].Date, End: days[1].Date, Text: "summary 2"},
+		{Start: days[2].Date, End: days[2].Date, Text: "summary 3"},
+	})
+	if text != want {
+		t.Errorf("summarizeNotes = %q, want %q", text, want)
+	}
+
+	// The weeks are cached, so the same notes need no more requests
+	again, err := summarizeNotes(context.Background(), CommandSettings{ContextTokens: 10000}, days, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != text || requests.Load() != 3 {
+		t.Errorf("second run = %q after %d requests, want the cached %q", again, requests.Load(), text)
+	}
+}
+
+func TestSummarizeNotesCombinesToFit(t *testing.T) {
+	requests := fakeSummarizer(t)
+	days := []dayNotes{marchNotes(17, "third week"), marchNotes(10, "second week"), marchNotes(3, "first week")}
+
+	// Room for one summary but not three
+	budget := estimateTokens(formatSummaries([]summaryPart{{Start: days[0].Date, End: days[2].Date, Text: "summary 10"}}))
+	text, err := summarizeNotes(context.Background(), CommandSettings{ContextTokens: budget}, days, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if estimateTokens(text) > budget {
+		t.Errorf("summary of %d tokens is over the budget of %d: %q", estimateTokens(text), budget, text)
+	}
+	if !strings.Contains(text, "March 3, 2026 to Tuesday, March 17, 2026") {
+		t.Errorf("summary %q doesn't cover every week", text)
+	}
+	if got := requests.Load(); got < 4 {
+		t.Errorf("made %d requests, want the weeks summarized and then combined", got)
+	}
+}
@@ -0,0 +1,384 @@
+package ai
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Template is the system prompt and user message for one AI command. Both
+// are Go templates filled in with TemplateData.
+type Template struct {
+	Name        string
+	Description string
+	System      string
+	User        string
+	Path        string // Override file, empty for the built-in template
+}
+
+// TemplateData is what templates can refer to, e.g. {{.Notes}}.
+type TemplateData struct {
+	Notes string // Recent entries, each under a "=== date ===" heading
+	Start string // First day covered, e.g. "March 3, 2025"
+	End   string // Last day covered
+	Days  int    // Days covered
+	Today string
+	Stats string // Writing statistics summary, for insights only
+
+	Question string // What was asked, for chat only
+
+	days []dayNotes // Notes by day, for summarizing notes too long to send whole
+}
+
+// newTemplateData describes the notes in days, read from r.
+func newTemplateData(days []dayNotes, r noteRange) TemplateData {
+	data := newTemplateText(formatNotes(days), r.Start, r.End)
+	data.days = days
+	return data
+}
+
+// newTemplateText describes notes already formatted, covering start to end.
+func newTemplateText(notes string, start, end time.Time) TemplateData {
+	return TemplateData{
+		Notes: notes,
+		Start: start.Format("January 2, 2006"),
+		End:   end.Format("January 2, 2006"),
+		Days:  noteRange{Start: start, End: end}.days(),
+		Today: time.Now().Format("Monday, January 2, 2006"),
+	}
+}
+
+// templateSeparator splits an override file into the system prompt and the
+// user message.
+const templateSeparator = "---"
+
+// builtinTemplates are the defaults, used when there's no override file.
+var builtinTemplates = []Template{
+	{
+		Name:        "think",
+		Description: "Categorized TODOs for `river think`",
+		System: `You are an AI assistant helping with personal productivity. Based on the provided notes from the last few days, generate organized lists of TODOs in different categories.
+
+Create the following sections:
+
+**WORK TODOs:**
+- Tasks related to professional projects, meetings, deadlines
+- Follow-ups with colleagues or clients
+- Work-related goals and commitments
+
+**PERSONAL TODOs:**
+- Personal tasks, household items, family commitments
+- Health, fitness, and self-care items
+- Financial and administrative tasks
+
+**PROJECTS & IDEAS:**
+- Ideas that need further exploration or research
+- Long-term goals and strategic thinking
+- Creative projects or learning opportunities
+- Reflection and planning items
+
+Focus on:
+- Incomplete tasks or projects mentioned
+- Follow-ups needed 
+- Ideas that could be developed further
+- Goals or commitments that need action
+- Any deadlines or time-sensitive items
+
+IMPORTANT: For each TODO item, include a brief rationale in parentheses that cites or references the specific note content that led to this suggestion. For example:
+"1. Follow up with John about the project proposal (mentioned meeting him on Tuesday but no follow-up scheduled)"
+
+Format your response with clear section headers and numbered lists under each. Be specific and concise. If a category has no clear TODOs, you may omit that section or suggest general productivity actions based on the content themes.`,
+		User: `Here are my notes from {{.Start}} to {{.End}} ({{.Days}} days):
+
+{{.Notes}}
+
+Please generate organized lists of TODOs based on this content, categorized by Work, Home, and Deeper Thought items.`,
+	},
+	{
+		Name:        "analyze",
+		Description: "Patterns and themes for `river analyze`",
+		System: `You are an AI assistant specialized in analyzing personal notes to identify patterns, themes, and insights. Based on the provided notes from the last few days, provide a thoughtful analysis.
+
+Please create the following sections:
+
+**THEMES & PATTERNS:**
+- Recurring topics or concerns that appear across multiple days
+- Emotional patterns or mood trends you notice
+- Productivity or energy level patterns
+- Common challenges or obstacles mentioned
+
+**KEY INSIGHTS:**
+- What the notes reveal about current priorities and focus areas
+- Potential blind spots or areas that might need attention
+- Connections between different thoughts or ideas
+- Progress or changes you can observe over time
+
+**OBSERVATIONS:**
+- Notable differences between days or shifts in thinking
+- Areas where there seems to be mental clarity vs confusion
+- Signs of growth, learning, or development
+- Recurring questions or curiosities
+
+Be thoughtful and nuanced in your analysis. Focus on helping the person understand their own thinking patterns and mental landscape. Cite specific examples from the notes when possible to support your observations.
+
+Format your response with clear section headers and insightful commentary. Be encouraging and constructive while being honest about what you observe.`,
+		User: `Here are my notes from {{.Start}} to {{.End}} ({{.Days}} days):
+
+{{.Notes}}
+
+Please analyze these notes for patterns, themes, and insights about my thinking and mental state.`,
+	},
+	{
+		Name:        "todo",
+		Description: "Actionable items for `river todo`",
+		System: `Extract ONLY the most concrete, actionable TODOs from the notes. Be extremely selective - only include items that:
+
+1. Are EXPLICITLY mentioned as tasks, commitments, or things to do
+2. Have a clear, specific action (call someone, buy something, schedule something, send something)
+3. Can be completed in a single, definable action
+
+DO NOT include:
+- Vague goals or aspirations
+- Things to "think about" or "consider"
+- Emotional work or self-improvement items
+- Ideas without clear next steps
+
+Format each TODO as:
+• [ACTION VERB] [SPECIFIC TASK]
+
+Examples of GOOD todos:
+• Call dentist to schedule cleaning
+• Send Q4 report to Sarah
+• Buy new running shoes
+• Schedule meeting with product team
+• Submit expense report for conference
+
+Examples of BAD todos (do not include):
+• Think about career goals
+• Be more organized
+• Work on project
+• Improve communication
+• Consider new strategies
+
+Keep the list SHORT (max 10 items). If there are no truly actionable items, return "No specific action items found in recent notes."
+
+Sort by urgency/importance when possible.`,
+		User: `Here are my notes from {{.Start}} to {{.End}} ({{.Days}} days):
+
+{{.Notes}}
+
+Please extract actionable TODOs from these notes.`,
+	},
+	{
+		Name:        "prompts",
+		Description: "Journal prompts for `river prompts`",
+		System: `You are an AI assistant that creates personalized journal prompts based on someone's recent journal entries. Your goal is to help them reflect more deeply, explore unresolved thoughts, and continue their personal growth journey.
+
+Based on their recent notes, generate 7 thoughtful journal prompts that:
+
+1. Build on themes and topics they've been exploring
+2. Help them dig deeper into unresolved questions or concerns
+3. Encourage reflection on patterns you notice
+4. Challenge them to think about things from new perspectives
+5. Support their goals and aspirations
+6. Address any emotional or mental patterns you observe
+7. Connect different ideas they've mentioned
+
+Guidelines:
+- Make prompts specific to their content, not generic
+- Reference specific topics, people, or situations they've mentioned when relevant
+- Vary the types of prompts (reflection, planning, gratitude, challenge, insight, etc.)
+- Keep prompts open-ended but focused
+- Make them thought-provoking but not overwhelming
+- Consider their current emotional state and energy level
+
+Format your response as a JSON array of strings, with exactly 7 prompts. Each prompt should be a complete question or writing prompt. Example format:
+["First prompt here?", "Second prompt here?", "Third prompt here?", "Fourth prompt here?", "Fifth prompt here?", "Sixth prompt here?", "Seventh prompt here?"]`,
+		User: `Here are my journal entries from {{.Start}} to {{.End}}:
+
+{{.Notes}}
+
+Please generate 7 personalized journal prompts based on these entries.`,
+	},
+	{
+		Name:        "insights",
+		Description: "Writing habit analysis for the stats Insights tab",
+		System: `You are an AI assistant that analyzes writing habits and journal statistics to provide personalized insights. Based on the provided statistics and recent journal entries, create a comprehensive analysis that helps the writer understand their patterns and improve their practice.
+
+Please provide insights in the following sections:
+
+**📊 PRODUCTIVITY PATTERNS**
+- Analyze writing frequency, volume trends, and time patterns
+- Identify peak productivity days/times if evident
+- Comment on consistency and streak patterns
+- Note any concerning gaps or declines
+
+**🎯 HABITS & CONSISTENCY**
+- Evaluate the strength of their writing habit
+- Comment on their streak performance
+- Suggest ways to improve consistency
+- Recognize achievements and milestones
+
+**💭 CONTENT THEMES**
+- Based on recent entries, identify recurring themes or concerns
+- Note any emotional patterns or mood trends
+- Highlight areas of focus or preoccupation
+- Suggest unexplored topics they might benefit from
+
+**🚀 RECOMMENDATIONS**
+- Provide 3-5 specific, actionable suggestions
+- Include both habit-building and content-focused advice
+- Suggest optimal writing times or goals based on their data
+- Recommend prompts or exercises based on their patterns
+
+Keep the tone encouraging but honest. Use data to support observations. Make recommendations specific and achievable. Format with clear headers and bullet points.`,
+		User: `{{.Stats}}
+
+Recent Journal Entries:
+{{.Notes}}
+
+Please analyze my writing patterns and provide personalized insights.`,
+	},
+	{
+		Name:        "chat",
+		Description: "Answers questions about the journal in `river chat`",
+		System: `You are helping someone explore their own journal. Answer their questions using the journal passages below, each under the date it was written.
+
+Cite the entries you draw on by date in square brackets, like [2025-03-14], right after the sentence they support. Only cite dates that appear below. The passages are the ones that best matched the question, not the whole journal, so if they don't answer it, say so plainly rather than guessing.
+
+Keep answers short and conversational, and speak to the writer as "you". Today is {{.Today}}.
+
+Journal passages:
+{{.Notes}}`,
+		User: `{{.Question}}`,
+	},
+	{
+		Name:        "summarize",
+		Description: "Condenses a week of notes when a long range is too much to send whole",
+		System: `You condense someone's journal notes into a faithful summary that will stand in for them in a later analysis. Keep what that analysis needs:
+
+- Events, people, places and decisions
+- Tasks, plans and open questions, including anything left unfinished
+- Moods, worries and what brought them on
+- Ideas and themes that come up more than once
+
+Write in the first person, as in the notes, using short bullet points. Stay under 250 words. Keep dates where they matter. Don't add advice, interpretation or anything the notes don't say.`,
+		User: `Here are my notes from {{.Start}} to {{.End}}:
+
+{{.Notes}}
+
+Please summarize them.`,
+	},
+}
+
+// templatesDir returns where override files live (~/river/prompts).
+func templatesDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, "river", "prompts"), nil
+}
+
+// builtinTemplate returns the default template called name.
+func builtinTemplate(name string) (Template, bool) {
+	for _, t := range builtinTemplates {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Template{}, false
+}
+
+// LoadTemplate returns the template called name, read from
+// ~/river/prompts/<name>.md when that file exists. The file holds the system
+// prompt, optionally followed by a "---" line and the user message; without
+// one the built-in user message is kept.
+func LoadTemplate(name string) (Template, error) {
+	t, ok := builtinTemplate(name)
+	if !ok {
+		return t, fmt.Errorf("unknown template %q, expected one of %s", name, strings.Join(TemplateNames(), ", "))
+	}
+
+	dir, err := templatesDir()
+	if err != nil {
+		return t, err
+	}
+	path := filepath.Join(dir, name+".md")
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return t, nil
+	}
+	if err != nil {
+		return t, err
+	}
+
+	t.Path = path
+	t.System = strings.TrimSpace(string(data))
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) == templateSeparator {
+			t.System = strings.TrimSpace(strings.Join(lines[:i], "\n"))
+			t.User = strings.TrimSpace(strings.Join(lines[i+1:], "\n"))
+			break
+		}
+	}
+	return t, nil
+}
+
+// TemplateNames lists every template.
+func TemplateNames() []string {
+	names := make([]string, len(builtinTemplates))
+	for i, t := range builtinTemplates {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// Source returns the template as it would be written to an override file.
+func (t Template) Source() string {
+	return t.System + "\n\n" + templateSeparator + "\n\n" + t.User + "\n"
+}
+
+// Render fills in the system prompt and user message.
+func (t Template) Render(data TemplateData) (system, user string, err error) {
+	system, err = t.render("system prompt", t.System, data)
+	if err != nil {
+		return "", "", err
+	}
+	user, err = t.render("user message", t.User, data)
+	if err != nil {
+		return "", "", err
+	}
+	return system, user, nil
+}
+
+func (t Template) render(part, text string, data TemplateData) (string, error) {
+	where := t.Name + " template"
+	if t.Path != "" {
+		where = t.Path
+	}
+
+	tmpl, err := template.New(t.Name).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("%s: %s: %v", where, part, err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("%s: %s: %v", where, part, err)
+	}
+	return b.String(), nil
+}
+
+// ResetTemplate removes the override for name, going back to the built-in
+// template. The old file is kept as <name>.md.bak.
+func ResetTemplate(name string) (bool, error) {
+	t, err := LoadTemplate(name)
+	if err != nil || t.Path == "" {
+		return false, err
+	}
+	return true, os.Rename(t.Path, t.Path+".bak")
+}
@@ -0,0 +1,379 @@
+package chatui
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/mattwhite/river-go/internal/ai"
+	"github.com/mattwhite/river-go/internal/editor"
+)
+
+var (
+	subtle    = lipgloss.AdaptiveColor{Light: "#D9DCCF", Dark: "#383838"}
+	highlight = lipgloss.AdaptiveColor{Light: "#874BFD", Dark: "#7D56F4"}
+	special   = lipgloss.AdaptiveColor{Light: "#43BF6D", Dark: "#73F59F"}
+	warning   = lipgloss.AdaptiveColor{Light: "#FF5F87", Dark: "#FF6F91"}
+
+	labelStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	questionStyle = lipgloss.NewStyle().Foreground(highlight).Bold(true)
+	citeStyle     = lipgloss.NewStyle().Foreground(special)
+	selectedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("0")).Background(special)
+)
+
+// citationPattern matches a date cited in an answer, e.g. [2025-03-14].
+var citationPattern = regexp.MustCompile(`\[\d{4}-\d{2}-\d{2}\]`)
+
+// turn is one question and its answer.
+type turn struct {
+	question  string
+	answer    string
+	citations []time.Time
+	err       error
+	stopped   bool // Interrupted before the answer was finished
+}
+
+// chunkMsg is the next piece of the answer being written.
+type chunkMsg string
+
+// answerMsg ends an answer.
+type answerMsg struct {
+	answer ai.Answer
+	err    error
+}
+
+// Model is a conversation with the journal. Citations in the latest answer
+// can be picked with tab and opened in the editor.
+type Model struct {
+	chat     *ai.Chat
+	input    textinput.Model
+	viewport viewport.Model
+	spinner  spinner.Model
+	width    int
+	height   int
+
+	turns     []turn
+	answering bool
+	cancel    context.CancelFunc
+	stream    chan tea.Msg
+	cursor    int // Selected citation in the latest answer, -1 for none
+
+	editing bool
+	editor  editor.Model
+}
+
+// New returns a conversation about the notes chat searches.
+func New(chat *ai.Chat) Model {
+	input := textinput.New()
+	input.Placeholder = "Ask about your journal..."
+	input.Prompt = "› "
+	input.CharLimit = 500
+	input.Focus()
+
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(highlight)
+
+	return Model{
+		chat:     chat,
+		input:    input,
+		viewport: viewport.New(0, 0),
+		spinner:  s,
+		cursor:   -1,
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// waitForStream delivers the next message about the answer being written.
+func waitForStream(stream chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-stream
+	}
+}
+
+// ask starts answering question in the background.
+func (m Model) ask(question string) (Model, tea.Cmd) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := make(chan tea.Msg, 64)
+	chat := m.chat
+	go func() {
+		answer, err := chat.Ask(ctx, question, func(text string) {
+			stream <- chunkMsg(text)
+		})
+		stream <- answerMsg{answer: answer, err: err}
+	}()
+
+	m.turns = append(m.turns, turn{question: question})
+	m.answering = true
+	m.cancel = cancel
+	m.stream = stream
+	m.cursor = -1
+	m.input.Reset()
+	return m, tea.Batch(m.spinner.Tick, waitForStream(stream))
+}
+
+// stop interrupts the answer being written.
+func (m Model) stop() Model {
+	if m.answering && m.cancel != nil {
+		m.cancel()
+		m.turns[len(m.turns)-1].stopped = true
+	}
+	return m
+}
+
+// latest returns the last turn, if there is one.
+func (m Model) latest() (turn, bool) {
+	if len(m.turns) == 0 {
+		return turn{}, false
+	}
+	return m.turns[len(m.turns)-1], true
+}
+
+// openEditor hands the screen to the editor for date until it is closed.
+func (m Model) openEditor(date time.Time) (Model, tea.Cmd) {
+	m.editing = true
+	m.editor = editor.NewEmbeddedModel(date)
+
+	ed, cmd := m.editor.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+	m.editor = ed.(editor.Model)
+	return m, tea.Batch(m.editor.Init(), cmd)
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m, cmd := m.update(msg)
+	m.syncViewport()
+	return m, cmd
+}
+
+func (m Model) update(msg tea.Msg) (Model, tea.Cmd) {
+	if m.editing {
+		switch msg.(type) {
+		case editor.ClosedMsg:
+			m.editing = false
+			return m, textinput.Blink
+		case tea.WindowSizeMsg, chunkMsg, answerMsg:
+			// Handled below, so the chat stays sized and any late answer lands
+		default:
+			ed, cmd := m.editor.Update(msg)
+			m.editor = ed.(editor.Model)
+			return m, cmd
+		}
+	}
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.input.Width = msg.Width - 4
+		if m.editing {
+			ed, cmd := m.editor.Update(msg)
+			m.editor = ed.(editor.Model)
+			return m, cmd
+		}
+		return m, nil
+
+	case chunkMsg:
+		m.turns[len(m.turns)-1].answer += string(msg)
+		m.viewport.GotoBottom()
+		return m, waitForStream(m.stream)
+
+	case answerMsg:
+		t := &m.turns[len(m.turns)-1]
+		t.answer = msg.answer.Text
+		t.citations = msg.answer.Citations
+		if msg.err != nil && !t.stopped {
+			t.err = msg.err
+		}
+		m.answering = false
+		m.cancel = nil
+		m.viewport.GotoBottom()
+		return m, nil
+
+	case spinner.TickMsg:
+		if !m.answering {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m Model) handleKey(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m.stop(), tea.Quit
+
+	case "esc":
+		switch {
+		case m.answering:
+			return m.stop(), nil
+		case m.cursor >= 0:
+			m.cursor = -1
+			return m, nil
+		}
+		return m, tea.Quit
+
+	case "tab", "shift+tab":
+		t, ok := m.latest()
+		if m.answering || !ok || len(t.citations) == 0 {
+			return m, nil
+		}
+		step := 1
+		if msg.String() == "shift+tab" {
+			step = len(t.citations) - 1
+		}
+		if m.cursor < 0 {
+			m.cursor = 0
+			if step != 1 {
+				m.cursor = len(t.citations) - 1
+			}
+		} else {
+			m.cursor = (m.cursor + step) % len(t.citations)
+		}
+		return m, nil
+
+	case "enter":
+		if t, ok := m.latest(); ok && m.cursor >= 0 && m.cursor < len(t.citations) {
+			return m.openEditor(t.citations[m.cursor])
+		}
+		question := strings.TrimSpace(m.input.Value())
+		if question == "" || m.answering {
+			return m, nil
+		}
+		return m.ask(question)
+
+	case "up", "down", "pgup", "pgdown":
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		return m, cmd
+	}
+
+	m.cursor = -1
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// syncViewport fits the transcript between the header and the input.
+func (m *Model) syncViewport() {
+	m.viewport.Width = m.width
+	m.viewport.Height = max(m.height-5, 1)
+	atBottom := m.viewport.AtBottom()
+	m.viewport.SetContent(m.renderTranscript())
+	if atBottom {
+		m.viewport.GotoBottom()
+	}
+}
+
+// renderAnswer styles the citations of entries in text, marking the
+// selected one. Dates without an entry are left plain.
+func renderAnswer(text string, citations []time.Time, selected int) string {
+	return citationPattern.ReplaceAllStringFunc(text, func(cite string) string {
+		for i, date := range citations {
+			if cite != "["+date.Format("2006-01-02")+"]" {
+				continue
+			}
+			if i == selected {
+				return selectedStyle.Render(cite)
+			}
+			return citeStyle.Render(cite)
+		}
+		return cite
+	})
+}
+
+func (m Model) renderTranscript() string {
+	width := max(min(m.width-4, 100), 20)
+	wrap := lipgloss.NewStyle().Width(width)
+
+	if len(m.turns) == 0 {
+		return wrap.Render(labelStyle.Render(fmt.Sprintf(
+			"Ask anything about your journal, like \"When did I last mention feeling burnt out?\" "+
+				"Each question is answered from the best matches among your %d paragraphs, "+
+				"and answers cite the entries they draw on.",
+			m.chat.Entries())))
+	}
+
+	var b strings.Builder
+	for i, t := range m.turns {
+		latest := i == len(m.turns)-1
+		b.WriteString(questionStyle.Render("You: ") + wrap.Render(t.question) + "\n\n")
+
+		selected := -1
+		if latest {
+			selected = m.cursor
+		}
+		switch {
+		case t.answer != "":
+			b.WriteString(wrap.Render(renderAnswer(strings.TrimSpace(t.answer), t.citations, selected)) + "\n")
+		case latest && m.answering:
+			b.WriteString(m.spinner.View() + labelStyle.Render(" Searching your entries...") + "\n")
+		}
+
+		switch {
+		case t.stopped:
+			b.WriteString(labelStyle.Render("⏹ Stopped.") + "\n")
+		case t.err != nil:
+			b.WriteString(wrap.Render(lipgloss.NewStyle().Foreground(warning).Render("✗ "+t.err.Error())) + "\n")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func (m Model) renderHelp() string {
+	t, _ := m.latest()
+	switch {
+	case m.answering:
+		return labelStyle.Render("esc stop • ↑/↓ scroll • ctrl+c quit")
+	case m.cursor >= 0:
+		return labelStyle.Render(fmt.Sprintf("enter open %s • tab next • esc back",
+			t.citations[m.cursor].Format("Mon, Jan 2, 2006")))
+	case len(t.citations) > 0:
+		return labelStyle.Render("enter ask • tab pick a cited entry • ↑/↓ scroll • esc quit")
+	}
+	return labelStyle.Render("enter ask • ↑/↓ scroll • esc quit")
+}
+
+func (m Model) View() string {
+	if m.editing {
+		return m.editor.View()
+	}
+	if m.width == 0 {
+		return ""
+	}
+
+	title := questionStyle.Render("💬 Chat with your journal")
+	if model := m.chat.Model(); model != "" {
+		title += labelStyle.Render("  " + model)
+	}
+	divider := lipgloss.NewStyle().Foreground(subtle).Render(strings.Repeat("─", m.width))
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		title,
+		divider,
+		m.viewport.View(),
+		divider,
+		m.input.View(),
+		m.renderHelp(),
+	)
+}
@@ -0,0 +1,100 @@
+package chatui
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/mattwhite/river-go/internal/ai"
+)
+
+// next returns the next message about the answer being written.
+func next(t *testing.T, m Model) tea.Msg {
+	t.Helper()
+	select {
+	case msg := <-m.stream:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("no word on the answer")
+		return nil
+	}
+}
+
+func update(m Model, msg tea.Msg) Model {
+	updated, _ := m.Update(msg)
+	return updated.(Model)
+}
+
+func TestStopWhileStreaming(t *testing.T) {
+	// The server starts an answer and never finishes it
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"Partial\"}}]}\n\n")
+		w.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.MkdirAll(filepath.Join(home, "river", "notes"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := "AI_PROVIDER=ollama\nAI_BASE_URL=" + server.URL + "\nAI_MODEL=test\n"
+	if err := os.WriteFile(filepath.Join(home, "river", ".config"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	chat, err := ai.NewChat(ai.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := update(New(chat), tea.WindowSizeMsg{Width: 80, Height: 24})
+	m.input.SetValue("How was March?")
+	m = update(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.answering {
+		t.Fatal("enter didn't start an answer")
+	}
+
+	msg := next(t, m)
+	if _, ok := msg.(chunkMsg); !ok {
+		t.Fatalf("got %T before the answer streamed, want chunkMsg", msg)
+	}
+	m = update(m, msg)
+
+	m = update(m, tea.KeyMsg{Type: tea.KeyEsc})
+	msg = next(t, m)
+	if _, ok := msg.(answerMsg); !ok {
+		t.Fatalf("got %T after stopping, want answerMsg", msg)
+	}
+	m = update(m, msg)
+
+	if m.answering || m.cancel != nil {
+		t.Error("still answering after stopping")
+	}
+	latest, _ := m.latest()
+	if !latest.stopped || latest.err != nil || latest.answer != "Partial" {
+		t.Errorf("stopped turn = %+v, want the partial answer, marked stopped without an error", latest)
+	}
+	if view := m.View(); !strings.Contains(view, "Stopped.") || strings.Contains(view, "✗") {
+		t.Errorf("view should show the answer as stopped, not failed:\n%s", view)
+	}
+
+	m.input.SetValue("And April?")
+	m = update(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.answering || len(m.turns) != 2 {
+		t.Errorf("couldn't ask again after stopping: answering %v, %d turns", m.answering, len(m.turns))
+	}
+	m.stop()
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Values holds the KEY=VALUE pairs stored in ~/river/.config.
+type Values map[string]string
+
+// Dir returns the River data directory (~/river).
+func Dir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, "river"), nil
+}
+
+func configPath() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, ".config"), nil
+}
+
+// Load reads the config file. A missing or unreadable file yields no values.
+func Load() Values {
+	values := make(Values)
+
+	path, err := configPath()
+	if err != nil {
+		return values
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return values
+	}
+
+	lines := strings.Split(string(data), "\n")
+	for _, line := range lines {
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) == 2 {
+			values[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+		}
+	}
+
+	return values
+}
+
+// Set stores key=value in the config file, keeping every other entry.
+func Set(key, value string) error {
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	values := Load()
+	values[key] = value
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var content strings.Builder
+	for _, k := range keys {
+		content.WriteString(fmt.Sprintf("%s=%s\n", k, values[k]))
+	}
+
+	return os.WriteFile(filepath.Join(dir, ".config"), []byte(content.String()), 0600)
+}
+
+// String returns the value for key, or def when it is unset.
+func (v Values) String(key, def string) string {
+	if value, ok := v[key]; ok && value != "" {
+		return value
+	}
+	return def
+}
+
+// Int returns the value for key as an integer, or def when it is unset or
+// not a number.
+func (v Values) Int(key string, def int) int {
+	value, ok := v[key]
+	if !ok {
+		return def
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+// Bool returns the value for key as a boolean, or def when it is unset or
+// not recognised.
+func (v Values) Bool(key string, def bool) bool {
+	value, ok := v[key]
+	if !ok {
+		return def
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return def
+	}
+	return b
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"strings"
+	"time"
+)
+
+// DefaultDailyGoal is the word goal used when DAILY_GOAL is not configured.
+const DefaultDailyGoal = 500
+
+// Goals describes what counts as a successful writing day or week.
+//
+// They are configured in ~/river/.config:
+//
+//	DAILY_GOAL=500            words per day (0 disables the word goal)
+//	GOAL_SATURDAY=200         per-weekday override of DAILY_GOAL
+//	WEEKLY_GOAL=3000          words per week (default: sum of the daily goals)
+//	DAILY_MINUTES_GOAL=15     minutes of writing per day
+//	STREAK_REQUIRES_GOAL=true only days that meet the goal extend a streak
+type Goals struct {
+	Daily              int
+	Weekday            map[time.Weekday]int
+	Weekly             int
+	DailyMinutes       int
+	StreakRequiresGoal bool
+}
+
+// LoadGoals reads the goal settings from the config file.
+func LoadGoals() Goals {
+	return GoalsFrom(Load())
+}
+
+// GoalsFrom builds goals from already loaded config values.
+func GoalsFrom(v Values) Goals {
+	g := Goals{
+		Daily:              v.Int("DAILY_GOAL", DefaultDailyGoal),
+		Weekday:            make(map[time.Weekday]int),
+		Weekly:             v.Int("WEEKLY_GOAL", 0),
+		DailyMinutes:       v.Int("DAILY_MINUTES_GOAL", 0),
+		StreakRequiresGoal: v.Bool("STREAK_REQUIRES_GOAL", false),
+	}
+
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		key := "GOAL_" + strings.ToUpper(day.String())
+		if _, ok := v[key]; ok {
+			g.Weekday[day] = v.Int(key, g.Daily)
+		}
+	}
+
+	return g
+}
+
+// WordsFor returns the word goal for date, honouring weekday overrides.
+func (g Goals) WordsFor(date time.Time) int {
+	if words, ok := g.Weekday[date.Weekday()]; ok {
+		return words
+	}
+	return g.Daily
+}
+
+// MinutesFor returns the writing time goal for date.
+func (g Goals) MinutesFor(date time.Time) time.Duration {
+	return time.Duration(g.DailyMinutes) * time.Minute
+}
+
+// WeeklyWords returns the word goal for the week starting at weekStart.
+func (g Goals) WeeklyWords(weekStart time.Time) int {
+	if g.Weekly > 0 {
+		return g.Weekly
+	}
+	total := 0
+	for i := 0; i < 7; i++ {
+		total += g.WordsFor(weekStart.AddDate(0, 0, i))
+	}
+	return total
+}
+
+// Met reports whether a day with the given words and writing time meets every
+// goal configured for date. A day without any goal is met by writing anything.
+func (g Goals) Met(date time.Time, words int, typingTime time.Duration) bool {
+	wordGoal := g.WordsFor(date)
+	timeGoal := g.MinutesFor(date)
+
+	if wordGoal <= 0 && timeGoal <= 0 {
+		return words > 0
+	}
+	if wordGoal > 0 && words < wordGoal {
+		return false
+	}
+	if timeGoal > 0 && typingTime < timeGoal {
+		return false
+	}
+	return true
+}
+
+// CountsForStreak reports whether a day with an entry extends a streak.
+func (g Goals) CountsForStreak(date time.Time, words int, typingTime time.Duration) bool {
+	if g.StreakRequiresGoal {
+		return g.Met(date, words, typingTime)
+	}
+	return true
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGoalsWordsFor(t *testing.T) {
+	monday := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	saturday := time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC)
+	sunday := time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		values Values
+		date   time.Time
+		want   int
+	}{
+		{"default", Values{}, monday, DefaultDailyGoal},
+		{"daily goal", Values{"DAILY_GOAL": "300"}, monday, 300},
+		{"override for another day", Values{"DAILY_GOAL": "300", "GOAL_SATURDAY": "100"}, monday, 300},
+		{"override for the day", Values{"DAILY_GOAL": "300", "GOAL_SATURDAY": "100"}, saturday, 100},
+		{"override without a daily goal", Values{"GOAL_SUNDAY": "50"}, sunday, 50},
+		{"override disables the day", Values{"GOAL_SUNDAY": "0"}, sunday, 0},
+		{"lower-case key is ignored", Values{"goal_sunday": "50"}, sunday, DefaultDailyGoal},
+		{"invalid override falls back to the daily goal", Values{"DAILY_GOAL": "300", "GOAL_SUNDAY": "lots"}, sunday, 300},
+		{"daily goal disabled", Values{"DAILY_GOAL": "0", "GOAL_MONDAY": "250"}, saturday, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GoalsFrom(tt.values).WordsFor(tt.date); got != tt.want {
+				t.Errorf("WordsFor(%s) = %d, want %d", tt.date.Weekday(), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoalsWeeklyWords(t *testing.T) {
+	monday := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		values Values
+		want   int
+	}{
+		{"sum of daily goals", Values{"DAILY_GOAL": "100"}, 700},
+		{"sum with overrides", Values{"DAILY_GOAL": "100", "GOAL_SATURDAY": "0", "GOAL_SUNDAY": "400"}, 900},
+		{"weekly goal wins", Values{"DAILY_GOAL": "100", "WEEKLY_GOAL": "2000"}, 2000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GoalsFrom(tt.values).WeeklyWords(monday); got != tt.want {
+				t.Errorf("WeeklyWords = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
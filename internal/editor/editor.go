@@ -11,39 +11,91 @@ import (
 	"github.com/charmbracelet/bubbles/textarea"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/mattwhite/river-go/internal/achievements"
+	"github.com/mattwhite/river-go/internal/ai"
+	"github.com/mattwhite/river-go/internal/config"
+	"github.com/mattwhite/river-go/internal/mood"
+	"github.com/mattwhite/river-go/internal/session"
+	"github.com/mattwhite/river-go/internal/statscache"
 )
 
+// ClosedMsg is sent instead of quitting when an embedded editor is closed,
+// so the program hosting it can take over again.
+type ClosedMsg struct {
+	Date time.Time
+}
+
 type Model struct {
 	textarea  textarea.Model
 	progress  progress.Model
 	filename  string
-	prompt    string // Today's prompt
+	date      time.Time // The day this entry belongs to
+	prompt    string    // The entry's prompt
+	embedded  bool      // Hosted inside another program rather than run on its own
 	width     int
 	height    int
 	ready     bool
 	wordCount int
+	tracker   session.Tracker
+	logged    []session.Session // Sessions logged before the editor opened
+	typedTime time.Duration     // Writing time logged earlier today
+	goals     config.Goals
+
+	mood       int  // 1-5, 0 until rated
+	askMood    bool // Whether saving asks for a mood rating
+	askingMood bool
+	moodAsked  bool // Asked this session, so don't ask again
+	quitting   bool // Quit once the mood question is answered
+
+	milestones    *achievements.Tracker
+	celebration   string // Shown in place of the help line for a moment
+	celebrationID int
+}
+
+// warningMsg reports something that went wrong in the background, shown in
+// the help line like a celebration.
+type warningMsg string
+
+// celebrationDoneMsg clears a celebration unless a newer one replaced it.
+type celebrationDoneMsg struct {
+	id int
 }
 
-func loadTodayFile() (content string, prompt string, filename string) {
-	// Get today's filename
-	today := time.Now().Format("2006-01-02")
+// celebrationTime is how long a celebration stays in the help line.
+const celebrationTime = 5 * time.Second
+
+// MoodFaces are shown next to the 1-5 mood ratings.
+var MoodFaces = []string{"😞", "🙁", "😐", "🙂", "😄"}
+
+// NotePath returns the file holding the entry for date.
+func NotePath(date time.Time) string {
 	homeDir, _ := os.UserHomeDir()
-	riverDir := filepath.Join(homeDir, "river", "notes")
-	filename = filepath.Join(riverDir, today+".md")
+	return filepath.Join(homeDir, "river", "notes", date.Format("2006-01-02")+".md")
+}
+
+func loadFile(date time.Time) (content string, prompt string, rating int, filename string) {
+	filename = NotePath(date)
 
 	// Ensure directory exists
-	os.MkdirAll(riverDir, 0755)
+	os.MkdirAll(filepath.Dir(filename), 0755)
 
 	// Try to read the file
 	data, err := os.ReadFile(filename)
 	if err != nil {
+		prompt = getPromptFor(date)
+
+		// Backfilled days are only written once there's something in them
+		if !isToday(date) {
+			return "", prompt, 0, filename
+		}
+
 		// File doesn't exist - create with template
-		prompt = getPromptForToday()
 		template := fmt.Sprintf("<!-- %s -->\n<!-- %s -->\n\n",
-			time.Now().Format("Monday, January 2, 2006"),
+			date.Format("Monday, January 2, 2006"),
 			prompt)
 		os.WriteFile(filename, []byte(template), 0644)
-		return "", prompt, filename
+		return "", prompt, 0, filename
 	}
 
 	// File exists - parse it
@@ -56,7 +108,11 @@ func loadTodayFile() (content string, prompt string, filename string) {
 		if strings.HasPrefix(trimmed, "<!--") && strings.HasSuffix(trimmed, "-->") {
 			// Extract prompt text
 			text := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(trimmed, "<!--"), "-->"))
-			if text != "" && !strings.Contains(text, "2025") && !strings.Contains(text, "2024") {
+			if r, ok := mood.Parse(text); ok {
+				rating = r
+				continue
+			}
+			if _, err := time.Parse("Monday, January 2, 2006", text); text != "" && err != nil {
 				prompts = append(prompts, text)
 			}
 		} else {
@@ -74,10 +130,14 @@ func loadTodayFile() (content string, prompt string, filename string) {
 		contentLines = contentLines[1:]
 	}
 
-	return strings.Join(contentLines, "\n"), prompt, filename
+	return strings.Join(contentLines, "\n"), prompt, rating, filename
 }
 
-func getPromptForToday() string {
+func isToday(date time.Time) bool {
+	return date.Format("2006-01-02") == time.Now().Format("2006-01-02")
+}
+
+func getPromptFor(date time.Time) string {
 	// Try to load prompts from the AI-generated prompts file
 	homeDir, _ := os.UserHomeDir()
 	riverDir := filepath.Join(homeDir, "river", "notes")
@@ -110,7 +170,7 @@ func getPromptForToday() string {
 				
 				if len(prompts) > 0 {
 					// Use day of year modulo number of prompts to select one
-					dayOfYear := time.Now().YearDay()
+					dayOfYear := date.YearDay()
 					return prompts[(dayOfYear-1)%len(prompts)]
 				}
 			}
@@ -131,7 +191,7 @@ func getPromptForToday() string {
 		"How have you grown lately?",
 	}
 
-	dayOfYear := time.Now().YearDay()
+	dayOfYear := date.YearDay()
 	return defaultPrompts[(dayOfYear-1)%len(defaultPrompts)]
 }
 
@@ -143,24 +203,60 @@ func countWords(text string) int {
 	return len(words)
 }
 
-func saveFile(filename string, content string, prompt string) error {
+func saveFile(filename string, date time.Time, content string, prompt string, rating int) error {
+	// Don't leave an empty file behind when a backfill is abandoned
+	if _, err := os.Stat(filename); os.IsNotExist(err) && strings.TrimSpace(content) == "" {
+		return nil
+	}
+
 	// Reconstruct file with prompt at top
 	var fullContent strings.Builder
 
 	// Add date and prompt as comments
-	fullContent.WriteString(fmt.Sprintf("<!-- %s -->\n", time.Now().Format("Monday, January 2, 2006")))
+	fullContent.WriteString(fmt.Sprintf("<!-- %s -->\n", date.Format("Monday, January 2, 2006")))
 	if prompt != "" {
 		fullContent.WriteString(fmt.Sprintf("<!-- %s -->\n", prompt))
 	}
+	if rating > 0 {
+		fullContent.WriteString(mood.Comment(rating) + "\n")
+	}
 	fullContent.WriteString("\n")
 	fullContent.WriteString(content)
 
-	return os.WriteFile(filename, []byte(fullContent.String()), 0644)
+	if err := os.WriteFile(filename, []byte(fullContent.String()), 0644); err != nil {
+		return err
+	}
+
+	// Keep the stats cache current so the dashboard opens instantly
+	return statscache.Update(filename)
+}
+
+// updateIndex refreshes the entry's place in the search index off the UI
+// goroutine, since that means rewriting the whole index.
+func updateIndex(filename string) tea.Cmd {
+	return func() tea.Msg {
+		if err := ai.UpdateIndex(filename); err != nil {
+			return warningMsg("⚠ Search index not updated: " + err.Error())
+		}
+		return nil
+	}
 }
 
 func NewInitialModel() Model {
-	// Load today's file
-	content, prompt, filename := loadTodayFile()
+	return NewModel(time.Now())
+}
+
+// NewEmbeddedModel returns an editor for date that sends ClosedMsg when the
+// writer is done instead of quitting the program.
+func NewEmbeddedModel(date time.Time) Model {
+	m := NewModel(date)
+	m.embedded = true
+	return m
+}
+
+// NewModel returns an editor for the entry on date.
+func NewModel(date time.Time) Model {
+	content, prompt, rating, filename := loadFile(date)
 
 	// Create textarea
 	ta := textarea.New()
@@ -182,12 +278,28 @@ func NewInitialModel() Model {
 	// Calculate initial word count
 	wordCount := countWords(content)
 
+	// Earlier sessions count towards the day's time goal
+	var typedTime time.Duration
+	sessions, _ := session.Load(date)
+	for _, s := range sessions {
+		typedTime += s.ActiveTime()
+	}
+
 	return Model{
 		textarea:  ta,
 		progress:  prog,
 		filename:  filename,
+		date:      date,
 		prompt:    prompt,
 		wordCount: wordCount,
+		tracker:   session.NewTracker(wordCount),
+		logged:    sessions,
+		typedTime: typedTime,
+		goals:     config.LoadGoals(),
+		mood:      rating,
+		askMood:   config.Load().Bool("ASK_MOOD", true),
+
+		milestones: achievements.NewTracker(date, wordCount, typedTime),
 	}
 }
 
@@ -238,25 +350,60 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.textarea.SetHeight(textAreaHeight)
 
 	case tea.KeyMsg:
+		if m.askingMood {
+			return m.answerMood(msg)
+		}
+
 		switch msg.Type {
 		case tea.KeyCtrlC, tea.KeyEsc:
-			// Save and quit
-			content := m.textarea.Value()
-			saveFile(m.filename, content, m.prompt)
-			return m, tea.Quit
+			// Save and quit, asking how the writer feels first
+			if m.needsMood() {
+				m.askingMood = true
+				m.quitting = true
+				return m, nil
+			}
+			return m.quit()
 
 		case tea.KeyCtrlS:
 			// Save
-			content := m.textarea.Value()
-			saveFile(m.filename, content, m.prompt)
+			if m.needsMood() {
+				m.askingMood = true
+				return m, nil
+			}
+			cmds = append(cmds, m.save())
 
 		default:
 			// Pass to textarea
+			prevText := m.textarea.Value()
 			m.textarea, cmd = m.textarea.Update(msg)
 			cmds = append(cmds, cmd)
 
+			// Only edits count as writing; moving around doesn't
+			text := m.textarea.Value()
+			if text == prevText {
+				break
+			}
+
 			// Update word count
-			m.wordCount = countWords(m.textarea.Value())
+			prevCount := m.wordCount
+			m.wordCount = countWords(text)
+			m.tracker.Record(time.Now(), m.wordCount)
+
+			// Celebrate any milestone the new words crossed
+			if m.wordCount != prevCount {
+				typed := m.typedTime + m.tracker.Active()
+				if messages := m.milestones.Update(m.wordCount, typed); len(messages) > 0 {
+					cmds = append(cmds, m.celebrate(strings.Join(messages, " • ")))
+				}
+			}
+		}
+
+	case warningMsg:
+		cmds = append(cmds, m.celebrate(string(msg)))
+
+	case celebrationDoneMsg:
+		if msg.id == m.celebrationID {
+			m.celebration = ""
 		}
 
 	case progress.FrameMsg:
@@ -273,6 +420,80 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// celebrate shows text in place of the help line for celebrationTime.
+func (m *Model) celebrate(text string) tea.Cmd {
+	m.celebrationID++
+	m.celebration = text
+	id := m.celebrationID
+	return tea.Tick(celebrationTime, func(time.Time) tea.Msg {
+		return celebrationDoneMsg{id: id}
+	})
+}
+
+// needsMood reports whether saving should ask for a mood rating first.
+func (m Model) needsMood() bool {
+	return m.askMood && m.mood == 0 && !m.moodAsked && m.wordCount > 0
+}
+
+// answerMood records a 1-5 rating, or skips the question on enter or esc, and
+// then finishes the save that asked for it.
+func (m Model) answerMood(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.Type == tea.KeyRunes && len(msg.Runes) == 1 && msg.Runes[0] >= '1' && msg.Runes[0] <= '5':
+		m.mood = int(msg.Runes[0] - '0')
+	case msg.Type == tea.KeyEnter, msg.Type == tea.KeyEsc, msg.Type == tea.KeyCtrlC:
+	default:
+		return m, nil
+	}
+
+	m.askingMood = false
+	m.moodAsked = true
+	if m.quitting {
+		return m.quit()
+	}
+	return m, m.save()
+}
+
+// write saves the entry and the writing sessions so far. It returns the
+// warning to show when either can't be written.
+func (m Model) write() (warningMsg, bool) {
+	if err := saveFile(m.filename, m.date, m.textarea.Value(), m.prompt, m.mood); err != nil {
+		return warningMsg("⚠ Couldn't save: " + err.Error()), false
+	}
+	if sessions := m.tracker.Sessions(); len(sessions) > 0 {
+		logged := append(m.logged[:len(m.logged):len(m.logged)], sessions...)
+		if err := session.Write(m.date, logged); err != nil {
+			return warningMsg("⚠ Couldn't save writing time: " + err.Error()), false
+		}
+	}
+	return "", true
+}
+
+// save writes the entry, then updates the search index in the background.
+func (m Model) save() tea.Cmd {
+	if warning, ok := m.write(); !ok {
+		return func() tea.Msg { return warning }
+	}
+	return updateIndex(m.filename)
+}
+
+// quit saves the entry and ends the session once the index is updated. When
+// the entry can't be saved the editor stays open to show why, so nothing is
+// lost without the writer knowing; a stale search index doesn't matter.
+func (m Model) quit() (tea.Model, tea.Cmd) {
+	m.quitting = false
+	if warning, ok := m.write(); !ok {
+		return m, func() tea.Msg { return warning }
+	}
+
+	done := tea.Quit
+	if m.embedded {
+		date := m.date
+		done = func() tea.Msg { return ClosedMsg{Date: date} }
+	}
+	return m, tea.Sequence(updateIndex(m.filename), done)
+}
+
 func (m Model) View() string {
 	if !m.ready {
 		return "Loading..."
@@ -305,9 +526,17 @@ func (m Model) View() string {
 
 	parts = append(parts, editorBox.Render(m.textarea.View()))
 
-	// Progress bar
-	const targetWords = 500
-	percent := float64(m.wordCount) / float64(targetWords)
+	// Progress bar tracks the word goal, or the time goal when there is none
+	wordGoal := m.goals.WordsFor(m.date)
+	timeGoal := m.goals.MinutesFor(m.date)
+	typed := m.typedTime + m.tracker.Active()
+
+	percent := 0.0
+	if wordGoal > 0 {
+		percent = float64(m.wordCount) / float64(wordGoal)
+	} else if timeGoal > 0 {
+		percent = float64(typed) / float64(timeGoal)
+	}
 	if percent > 1.0 {
 		percent = 1.0
 	}
@@ -323,7 +552,39 @@ func (m Model) View() string {
 		Foreground(lipgloss.Color("240")).
 		Padding(0, 2)
 
-	helpText := fmt.Sprintf("%d words • ^S save • ^C quit", m.wordCount)
+	goalText := fmt.Sprintf("%d words", m.wordCount)
+	if wordGoal > 0 {
+		goalText = fmt.Sprintf("%d/%d words", m.wordCount, wordGoal)
+	}
+	if timeGoal > 0 {
+		goalText += fmt.Sprintf(" • %d/%d min", int(typed.Minutes()), int(timeGoal.Minutes()))
+	}
+	if m.goals.Met(m.date, m.wordCount, typed) {
+		goalText += " ✓"
+	}
+
+	if m.mood > 0 {
+		goalText += " • " + MoodFaces[m.mood-1]
+	}
+
+	helpText := fmt.Sprintf("%s • ^S save • ^C quit", goalText)
+	if m.embedded {
+		helpText = fmt.Sprintf("%s • %s • ^S save • esc back", m.date.Format("Mon, Jan 2"), goalText)
+	}
+	if m.celebration != "" {
+		helpText = m.celebration
+		helpStyle = helpStyle.Foreground(lipgloss.Color("212")).Bold(true)
+	}
+
+	if m.askingMood {
+		var faces []string
+		for i, face := range MoodFaces {
+			faces = append(faces, fmt.Sprintf("%d %s", i+1, face))
+		}
+		helpText = "How are you feeling? " + strings.Join(faces, "  ") + " • enter skip"
+		helpStyle = helpStyle.Foreground(lipgloss.Color("62"))
+	}
+
 	parts = append(parts, helpStyle.Render(helpText))
 
 	return lipgloss.JoinVertical(lipgloss.Left, parts...)
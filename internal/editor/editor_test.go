@@ -0,0 +1,65 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestQuitOnlyAfterSaving(t *testing.T) {
+	tests := []struct {
+		name   string
+		broken bool // Whether the entry's directory can't be written
+	}{
+		{"saved", false},
+		{"save fails", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+
+			m := NewEmbeddedModel(time.Date(2026, 3, 4, 0, 0, 0, 0, time.Local))
+			m.textarea.SetValue("Some words worth keeping")
+			if tt.broken {
+				// A file where the directory should be
+				blocker := filepath.Join(home, "blocker")
+				if err := os.WriteFile(blocker, nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+				m.filename = filepath.Join(blocker, "2026-03-04.md")
+			}
+
+			updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+			if cmd == nil {
+				t.Fatal("esc did nothing")
+			}
+			msg := cmd()
+			warning, warned := msg.(warningMsg)
+
+			if tt.broken {
+				if !warned || !strings.Contains(string(warning), "Couldn't save") {
+					t.Fatalf("esc with a failing save gave %#v, want a warning instead of closing", msg)
+				}
+				updated, _ = updated.Update(warning)
+				if got := updated.(Model).celebration; got != string(warning) {
+					t.Errorf("showing %q, want the warning", got)
+				}
+				return
+			}
+
+			if warned {
+				t.Fatalf("esc after a good save warned %q", warning)
+			}
+			data, err := os.ReadFile(m.filename)
+			if err != nil || !strings.Contains(string(data), "Some words worth keeping") {
+				t.Errorf("entry = %q, %v; want the text saved", data, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package language
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Metrics describes the language of one entry. Counts rather than ratios are
+// kept so that entries can be added up into periods.
+type Metrics struct {
+	Words     int            `json:"words"`
+	Unique    int            `json:"unique"`
+	Sentences int            `json:"sentences"`
+	Syllables int            `json:"syllables"`
+	Terms     map[string]int `json:"terms,omitempty"` // Counts of words that aren't stopwords
+}
+
+// Term is a word and how characteristic it is of a set of entries.
+type Term struct {
+	Word  string
+	Count int
+	Score float64
+}
+
+// stopwords are too common to say anything about what an entry is about.
+var stopwords = toSet(`a about above after again against all also am an and any are
+as at be because been before being below between both but by can could did do does
+doing down during each even every few for from further get got had has have having he
+her here hers herself him himself his how i i'd i'll i'm i've if in into is it it's its
+itself just like me more most much my myself no nor not now of off on once one only or
+other our ours ourselves out over own really same she should so some still such than
+that that's the their theirs them themselves then there these they this those through
+to too under until up us very was we were what when where which while who whom why will
+with would you your yours yourself yourselves today day going went go make made thing
+things lot bit maybe think thought know want felt feel`)
+
+func toSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.Fields(words) {
+		set[word] = true
+	}
+	return set
+}
+
+// words splits text into lower-case words, keeping apostrophes inside them.
+func words(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+
+	out := fields[:0]
+	for _, field := range fields {
+		if field = strings.Trim(field, "'"); field != "" {
+			out = append(out, field)
+		}
+	}
+	return out
+}
+
+// Analyze measures text.
+func Analyze(text string) Metrics {
+	m := Metrics{Terms: make(map[string]int)}
+
+	seen := make(map[string]bool)
+	for _, word := range words(text) {
+		m.Words++
+		m.Syllables += syllables(word)
+		if !seen[word] {
+			seen[word] = true
+			m.Unique++
+		}
+		if !stopwords[word] && len([]rune(word)) > 2 {
+			m.Terms[word]++
+		}
+	}
+
+	// A sentence ends at a run of terminators; trailing text without one
+	// still counts
+	inSentence := false
+	for _, r := range text {
+		switch {
+		case r == '.' || r == '!' || r == '?':
+			if inSentence {
+				m.Sentences++
+			}
+			inSentence = false
+		case unicode.IsLetter(r):
+			inSentence = true
+		}
+	}
+	if inSentence {
+		m.Sentences++
+	}
+
+	return m
+}
+
+// syllables estimates the syllables in word by counting vowel groups.
+func syllables(word string) int {
+	count := 0
+	prevVowel := false
+	for _, r := range word {
+		vowel := strings.ContainsRune("aeiouy", r)
+		if vowel && !prevVowel {
+			count++
+		}
+		prevVowel = vowel
+	}
+	if strings.HasSuffix(word, "e") && !strings.HasSuffix(word, "le") && count > 1 {
+		count--
+	}
+	return max(1, count)
+}
+
+// Add returns the totals of m and other, as if they were one text. Unique
+// words can't be combined exactly, so they are summed.
+func (m Metrics) Add(other Metrics) Metrics {
+	return Metrics{
+		Words:     m.Words + other.Words,
+		Unique:    m.Unique + other.Unique,
+		Sentences: m.Sentences + other.Sentences,
+		Syllables: m.Syllables + other.Syllables,
+	}
+}
+
+// UniqueRatio returns the share of words that are distinct, from 0 to 1.
+func (m Metrics) UniqueRatio() float64 {
+	if m.Words == 0 {
+		return 0
+	}
+	return float64(m.Unique) / float64(m.Words)
+}
+
+// SentenceLength returns the average number of words per sentence.
+func (m Metrics) SentenceLength() float64 {
+	if m.Sentences == 0 {
+		return 0
+	}
+	return float64(m.Words) / float64(m.Sentences)
+}
+
+// Readability returns the Flesch reading ease score from 0 to 100: higher is
+// easier, and everyday prose sits around 60-70.
+func (m Metrics) Readability() float64 {
+	if m.Words == 0 || m.Sentences == 0 {
+		return 0
+	}
+	score := 206.835 - 1.015*m.SentenceLength() - 84.6*float64(m.Syllables)/float64(m.Words)
+	return math.Max(0, math.Min(100, score))
+}
+
+// ReadabilityLabel puts a reading ease score into words.
+func ReadabilityLabel(score float64) string {
+	switch {
+	case score >= 90:
+		return "very easy"
+	case score >= 80:
+		return "easy"
+	case score >= 70:
+		return "fairly easy"
+	case score >= 60:
+		return "plain English"
+	case score >= 50:
+		return "fairly difficult"
+	case score >= 30:
+		return "difficult"
+	default:
+		return "very difficult"
+	}
+}
+
+// TopTerms returns the n words that best characterize docs by TF-IDF. The
+// inverse document frequency comes from corpus, so words common everywhere
+// rank below words particular to docs.
+func TopTerms(docs, corpus []map[string]int, n int) []Term {
+	df := make(map[string]int)
+	for _, doc := range corpus {
+		for word := range doc {
+			df[word]++
+		}
+	}
+
+	counts := make(map[string]int)
+	scores := make(map[string]float64)
+	for _, doc := range docs {
+		total := 0
+		for _, count := range doc {
+			total += count
+		}
+		for word, count := range doc {
+			idf := math.Log(float64(len(corpus)+1) / float64(df[word]+1))
+			counts[word] += count
+			scores[word] += float64(count) / float64(total) * (idf + 1)
+		}
+	}
+
+	terms := make([]Term, 0, len(scores))
+	for word, score := range scores {
+		terms = append(terms, Term{Word: word, Count: counts[word], Score: score})
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if terms[i].Score != terms[j].Score {
+			return terms[i].Score > terms[j].Score
+		}
+		return terms[i].Word < terms[j].Word
+	})
+
+	return terms[:min(n, len(terms))]
+}
@@ -0,0 +1,61 @@
+package language
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want Metrics
+	}{
+		{
+			name: "empty text",
+			want: Metrics{Terms: map[string]int{}},
+		},
+		{
+			name: "punctuation only",
+			text: "...!? -- ?!",
+			want: Metrics{Terms: map[string]int{}},
+		},
+		{
+			name: "paragraph",
+			text: "The river ran high. We walked along it, and the river sang!",
+			want: Metrics{
+				Words:     12,
+				Unique:    10,
+				Sentences: 2,
+				Syllables: 16,
+				Terms:     map[string]int{"river": 2, "ran": 1, "high": 1, "walked": 1, "along": 1, "sang": 1},
+			},
+		},
+		{
+			name: "runs of terminators and a trailing sentence",
+			text: "Wait... what?! I'll 'stay' here",
+			want: Metrics{
+				Words:     5,
+				Unique:    5,
+				Sentences: 3,
+				Syllables: 5,
+				Terms:     map[string]int{"wait": 1, "stay": 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Analyze(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Analyze(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetricsRatiosWithoutWords(t *testing.T) {
+	m := Analyze("?!")
+	if m.UniqueRatio() != 0 || m.SentenceLength() != 0 || m.Readability() != 0 {
+		t.Errorf("ratios of %+v = %v, %v, %v, want all 0", m, m.UniqueRatio(), m.SentenceLength(), m.Readability())
+	}
+}
@@ -0,0 +1,25 @@
+package mood
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Parse reads the text of a "mood: N" comment.
+func Parse(comment string) (int, bool) {
+	value, found := strings.CutPrefix(strings.TrimSpace(comment), "mood:")
+	if !found {
+		return 0, false
+	}
+	rating, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || rating < 1 || rating > 5 {
+		return 0, false
+	}
+	return rating, true
+}
+
+// Comment returns the header line that records a 1-5 rating.
+func Comment(rating int) string {
+	return fmt.Sprintf("<!-- mood: %d -->", rating)
+}
@@ -4,12 +4,13 @@ import (
 	"bufio"
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/mattwhite/river-go/internal/config"
 )
 
 var (
@@ -122,40 +123,7 @@ func (m Model) View() string {
 }
 
 func saveAPIKey(apiKey string) error {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return err
-	}
-
-	riverDir := filepath.Join(homeDir, "river")
-	if err := os.MkdirAll(riverDir, 0755); err != nil {
-		return err
-	}
-
-	configPath := filepath.Join(riverDir, ".config")
-	
-	// Read existing config if it exists
-	existingConfig := make(map[string]string)
-	if data, err := os.ReadFile(configPath); err == nil {
-		lines := strings.Split(string(data), "\n")
-		for _, line := range lines {
-			parts := strings.SplitN(line, "=", 2)
-			if len(parts) == 2 {
-				existingConfig[parts[0]] = parts[1]
-			}
-		}
-	}
-
-	// Update with new API key
-	existingConfig["ANTHROPIC_API_KEY"] = apiKey
-
-	// Write back config
-	var configContent strings.Builder
-	for key, value := range existingConfig {
-		configContent.WriteString(fmt.Sprintf("%s=%s\n", key, value))
-	}
-
-	return os.WriteFile(configPath, []byte(configContent.String()), 0600)
+	return config.Set("ANTHROPIC_API_KEY", apiKey)
 }
 
 func LoadAPIKey() string {
@@ -165,29 +133,15 @@ func LoadAPIKey() string {
 	}
 
 	// Then check config file
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return ""
-	}
-
-	configPath := filepath.Join(homeDir, "river", ".config")
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		return ""
-	}
-
-	lines := strings.Split(string(data), "\n")
-	for _, line := range lines {
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) == 2 && parts[0] == "ANTHROPIC_API_KEY" {
-			return strings.TrimSpace(parts[1])
-		}
-	}
-
-	return ""
+	return config.Load().String("ANTHROPIC_API_KEY", "")
 }
 
+// NeedsOnboarding reports whether AI features are missing an Anthropic API
+// key. Other providers are set up in the config file instead.
 func NeedsOnboarding() bool {
+	if provider := config.Load().String("AI_PROVIDER", "anthropic"); !strings.EqualFold(provider, "anthropic") {
+		return false
+	}
 	return LoadAPIKey() == ""
 }
 
@@ -0,0 +1,81 @@
+package sentiment
+
+import (
+	"strings"
+	"unicode"
+)
+
+// lexicon scores words from -3 (very negative) to 3 (very positive). It is a
+// small hand-picked list aimed at journal writing rather than a full lexicon.
+var lexicon = map[string]int{
+	// Positive
+	"amazing": 3, "awesome": 3, "beautiful": 3, "blessed": 3, "brilliant": 3,
+	"delighted": 3, "ecstatic": 3, "excellent": 3, "fantastic": 3, "incredible": 3,
+	"joy": 3, "joyful": 3, "love": 3, "loved": 3, "loving": 3, "perfect": 3,
+	"thrilled": 3, "wonderful": 3,
+	"accomplished": 2, "appreciate": 2, "better": 2, "calm": 2, "cheerful": 2,
+	"confident": 2, "content": 2, "energized": 2, "enjoy": 2, "enjoyed": 2,
+	"excited": 2, "fun": 2, "glad": 2, "grateful": 2, "great": 2, "happy": 2,
+	"hope": 2, "hopeful": 2, "inspired": 2, "laugh": 2, "laughed": 2, "lovely": 2,
+	"motivated": 2, "peaceful": 2, "pleased": 2, "productive": 2, "proud": 2,
+	"relaxed": 2, "relieved": 2, "rested": 2, "success": 2, "thankful": 2,
+	"win": 2, "won": 2,
+	"fine": 1, "focused": 1, "free": 1, "friend": 1, "friends": 1, "good": 1,
+	"healthy": 1, "helpful": 1, "interesting": 1, "kind": 1, "like": 1,
+	"liked": 1, "nice": 1, "okay": 1, "progress": 1, "ready": 1, "safe": 1,
+	"smile": 1, "solid": 1, "steady": 1, "support": 1, "warm": 1,
+
+	// Negative
+	"awful": -3, "depressed": -3, "devastated": -3, "hate": -3, "hated": -3,
+	"hopeless": -3, "horrible": -3, "miserable": -3, "terrible": -3,
+	"worst": -3, "worthless": -3, "panic": -3,
+	"afraid": -2, "angry": -2, "annoyed": -2, "anxious": -2, "ashamed": -2,
+	"bad": -2, "bored": -2, "broken": -2, "cried": -2, "cry": -2, "difficult": -2,
+	"disappointed": -2, "drained": -2, "exhausted": -2, "failed": -2, "failure": -2,
+	"frustrated": -2, "guilty": -2, "hurt": -2, "lonely": -2, "lost": -2,
+	"overwhelmed": -2, "pain": -2, "sad": -2, "scared": -2, "sick": -2,
+	"stressed": -2, "stuck": -2, "upset": -2, "worried": -2, "worse": -2,
+	"busy": -1, "confused": -1, "doubt": -1, "hard": -1, "late": -1, "meh": -1,
+	"nervous": -1, "problem": -1, "problems": -1, "tense": -1, "tired": -1,
+	"unsure": -1, "worry": -1,
+}
+
+// negations flip the score of the word that follows them.
+var negations = map[string]bool{
+	"not": true, "no": true, "never": true, "isn't": true, "wasn't": true,
+	"don't": true, "didn't": true, "can't": true, "couldn't": true,
+	"won't": true, "hardly": true,
+}
+
+// Score returns the sentiment of text from -1 (negative) to 1 (positive),
+// averaged over the words found in the lexicon. Text with no scored words is
+// neutral.
+func Score(text string) float64 {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+
+	total, scored := 0, 0
+	negate := false
+	for _, word := range words {
+		word = strings.Trim(word, "'")
+		if negations[word] {
+			negate = true
+			continue
+		}
+
+		if score, ok := lexicon[word]; ok {
+			if negate {
+				score = -score
+			}
+			total += score
+			scored++
+		}
+		negate = false
+	}
+
+	if scored == 0 {
+		return 0
+	}
+	return float64(total) / float64(scored) / 3
+}
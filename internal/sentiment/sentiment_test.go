@@ -0,0 +1,34 @@
+package sentiment
+
+import (
+	"math"
+	"testing"
+)
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want float64
+	}{
+		{"empty", "", 0},
+		{"no scored words", "Went to the shop and came back.", 0},
+		{"positive", "I feel happy", 2.0 / 3},
+		{"opposites cancel out", "Amazing, then awful.", 0},
+		{"capitals and punctuation", "Wonderful!", 1},
+		{"average of scored words", "Great day, terrible night", -1.0 / 6},
+		{"negation flips the next word", "I am not happy", -2.0 / 3},
+		{"contracted negation", "I don't love it", -1},
+		{"negation only reaches one word", "not very happy", 2.0 / 3},
+		{"quotes around a word", "'happy'", 2.0 / 3},
+		{"most negative", "miserable and hopeless", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Score(tt.text); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Score(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package session
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// IdleTimeout is the longest pause between keystrokes that still counts
+	// as writing time. Longer gaps are treated as idle and ignored.
+	IdleTimeout = 2 * time.Minute
+
+	// SessionBreak is the pause after which the next keystroke starts a new
+	// session instead of continuing the current one.
+	SessionBreak = 30 * time.Minute
+)
+
+// Session is one stretch of writing in the editor.
+type Session struct {
+	Start         time.Time `json:"start"`
+	End           time.Time `json:"end"`
+	ActiveSeconds int       `json:"active_seconds"`
+	WordsAdded    int       `json:"words_added"`
+}
+
+// ActiveTime returns the time spent typing, excluding idle gaps.
+func (s Session) ActiveTime() time.Duration {
+	return time.Duration(s.ActiveSeconds) * time.Second
+}
+
+// Tracker measures writing activity from a stream of keystrokes.
+type Tracker struct {
+	running    bool
+	start      time.Time
+	last       time.Time
+	active     time.Duration
+	startWords int
+	words      int
+	finished   []Session
+}
+
+// NewTracker returns a tracker for a document that currently has words words.
+func NewTracker(words int) Tracker {
+	return Tracker{words: words}
+}
+
+// Record notes a keystroke at now, after which the document has words words.
+func (t *Tracker) Record(now time.Time, words int) {
+	if t.running && now.Sub(t.last) > SessionBreak {
+		t.finish()
+	}
+
+	if !t.running {
+		t.running = true
+		t.start = now
+		t.last = now
+		t.active = 0
+		t.startWords = t.words
+	}
+
+	if gap := now.Sub(t.last); gap > 0 && gap <= IdleTimeout {
+		t.active += gap
+	}
+	t.last = now
+	t.words = words
+}
+
+// Active returns the writing time recorded so far.
+func (t *Tracker) Active() time.Duration {
+	active := t.active
+	if !t.running {
+		active = 0
+	}
+	for _, s := range t.finished {
+		active += s.ActiveTime()
+	}
+	return active
+}
+
+// Sessions returns every session recorded so far, including the one still
+// running, which carries on with the next keystroke.
+func (t *Tracker) Sessions() []Session {
+	sessions := append([]Session(nil), t.finished...)
+	if t.running {
+		sessions = append(sessions, t.current())
+	}
+	return sessions
+}
+
+func (t *Tracker) finish() {
+	t.finished = append(t.finished, t.current())
+	t.running = false
+}
+
+func (t *Tracker) current() Session {
+	added := t.words - t.startWords
+	if added < 0 {
+		added = 0
+	}
+
+	return Session{
+		Start:         t.start,
+		End:           t.last,
+		ActiveSeconds: int(t.active.Seconds()),
+		WordsAdded:    added,
+	}
+}
+
+// logDir returns the directory holding the per-day session logs.
+func logDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, "river", "notes", ".sessions"), nil
+}
+
+// Write replaces the log for the entry dated date with sessions. The log is
+// written to a temporary file first, so a crash never leaves half of it.
+func Write(date time.Time, sessions []Session) error {
+	dir, err := logDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, s := range sessions {
+		if err := enc.Encode(s); err != nil {
+			return err
+		}
+	}
+
+	filename := filepath.Join(dir, date.Format("2006-01-02")+".jsonl")
+	tmp := filename + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
+
+// Load returns the sessions logged for the entry dated date.
+func Load(date time.Time) ([]Session, error) {
+	dir, err := logDir()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := readLog(filepath.Join(dir, date.Format("2006-01-02")+".jsonl"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return sessions, err
+}
+
+// LoadAll returns every logged session keyed by entry date (2006-01-02).
+func LoadAll() (map[string][]Session, error) {
+	dir, err := logDir()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+
+	all := make(map[string][]Session)
+	for _, file := range files {
+		dateStr := strings.TrimSuffix(filepath.Base(file), ".jsonl")
+		if _, err := time.Parse("2006-01-02", dateStr); err != nil {
+			continue
+		}
+
+		sessions, err := readLog(file)
+		if err != nil {
+			continue
+		}
+		all[dateStr] = sessions
+	}
+
+	return all, nil
+}
+
+func readLog(filename string) ([]Session, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var sessions []Session
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var s Session
+		if err := json.Unmarshal([]byte(line), &s); err != nil {
+			// Skip a partially written line rather than losing the whole day
+			continue
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, scanner.Err()
+}
@@ -0,0 +1,100 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrackerSessions(t *testing.T) {
+	start := time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC)
+	at := func(offset time.Duration) time.Time { return start.Add(offset) }
+
+	type keystroke struct {
+		at    time.Duration
+		words int
+	}
+
+	tests := []struct {
+		name       string
+		words      int // Words before the first keystroke
+		keystrokes []keystroke
+		want       []Session
+	}{
+		{
+			name: "no keystrokes",
+		},
+		{
+			name:       "steady typing",
+			keystrokes: []keystroke{{0, 1}, {30 * time.Second, 2}, {90 * time.Second, 5}},
+			want:       []Session{{Start: at(0), End: at(90 * time.Second), ActiveSeconds: 90, WordsAdded: 5}},
+		},
+		{
+			name:       "pause at the idle limit still counts",
+			keystrokes: []keystroke{{0, 1}, {IdleTimeout, 2}},
+			want:       []Session{{Start: at(0), End: at(IdleTimeout), ActiveSeconds: 120, WordsAdded: 2}},
+		},
+		{
+			name:       "idle pause is left out",
+			keystrokes: []keystroke{{0, 1}, {10 * time.Second, 2}, {10*time.Minute + 10*time.Second, 3}},
+			want:       []Session{{Start: at(0), End: at(10*time.Minute + 10*time.Second), ActiveSeconds: 10, WordsAdded: 3}},
+		},
+		{
+			name:       "pause at the break limit continues the session",
+			keystrokes: []keystroke{{0, 1}, {SessionBreak, 2}},
+			want:       []Session{{Start: at(0), End: at(SessionBreak), ActiveSeconds: 0, WordsAdded: 2}},
+		},
+		{
+			name:       "long pause starts a new session",
+			words:      10,
+			keystrokes: []keystroke{{0, 11}, {time.Minute, 12}, {time.Hour, 13}, {time.Hour + 20*time.Second, 15}},
+			want: []Session{
+				{Start: at(0), End: at(time.Minute), ActiveSeconds: 60, WordsAdded: 2},
+				{Start: at(time.Hour), End: at(time.Hour + 20*time.Second), ActiveSeconds: 20, WordsAdded: 3},
+			},
+		},
+		{
+			name:       "deleting words adds none",
+			words:      50,
+			keystrokes: []keystroke{{0, 49}, {5 * time.Second, 40}},
+			want:       []Session{{Start: at(0), End: at(5 * time.Second), ActiveSeconds: 5, WordsAdded: 0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := NewTracker(tt.words)
+			for _, k := range tt.keystrokes {
+				tracker.Record(at(k.at), k.words)
+			}
+
+			got := tracker.Sessions()
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d sessions %+v, want %d", len(got), got, len(tt.want))
+			}
+			var active time.Duration
+			for i := range got {
+				if !got[i].Start.Equal(tt.want[i].Start) || !got[i].End.Equal(tt.want[i].End) ||
+					got[i].ActiveSeconds != tt.want[i].ActiveSeconds || got[i].WordsAdded != tt.want[i].WordsAdded {
+					t.Errorf("session %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+				active += tt.want[i].ActiveTime()
+			}
+			if tracker.Active() != active {
+				t.Errorf("Active() = %v, want %v", tracker.Active(), active)
+			}
+		})
+	}
+}
+
+func TestTrackerSessionsKeepsRunning(t *testing.T) {
+	start := time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC)
+	tracker := NewTracker(0)
+	tracker.Record(start, 1)
+	tracker.Sessions()
+	tracker.Record(start.Add(time.Minute), 2)
+
+	got := tracker.Sessions()
+	if len(got) != 1 || got[0].ActiveSeconds != 60 || got[0].WordsAdded != 2 {
+		t.Errorf("Sessions() after a snapshot = %+v, want one 60s session adding 2 words", got)
+	}
+}
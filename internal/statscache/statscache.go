@@ -0,0 +1,260 @@
+package statscache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattwhite/river-go/internal/language"
+	"github.com/mattwhite/river-go/internal/mood"
+	"github.com/mattwhite/river-go/internal/sentiment"
+)
+
+// version is bumped whenever Entry gains fields, forcing a rebuild.
+const version = 3
+
+// Entry holds everything the stats need from one note file.
+type Entry struct {
+	Path      string           `json:"path"`
+	Size      int64            `json:"size"`
+	ModTime   time.Time        `json:"mod_time"`
+	Date      string           `json:"date"` // 2006-01-02
+	Words     int              `json:"words"`
+	Prompt    string           `json:"prompt,omitempty"`
+	Mood      int              `json:"mood,omitempty"` // 1-5, 0 when not rated
+	Sentiment float64          `json:"sentiment"`      // -1 to 1
+	Language  language.Metrics `json:"language"`
+}
+
+type cacheFile struct {
+	Version int              `json:"version"`
+	Entries map[string]Entry `json:"entries"` // Keyed by path
+}
+
+func notesDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, "river", "notes"), nil
+}
+
+func cachePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, "river", ".cache", "stats.json"), nil
+}
+
+// Load returns an entry for every note, sorted by date. Only files whose
+// size or modification time changed since the last call are read again.
+func Load() ([]Entry, error) {
+	dir, err := notesDir()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.md"))
+	if err != nil {
+		return nil, err
+	}
+
+	cache := readCache()
+	fresh := make(map[string]Entry, len(files))
+	changed := false
+
+	for _, file := range files {
+		base := filepath.Base(file)
+		if strings.HasPrefix(base, ".") {
+			continue
+		}
+		dateStr := strings.TrimSuffix(base, ".md")
+		if _, err := time.Parse("2006-01-02", dateStr); err != nil {
+			continue
+		}
+
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+
+		if entry, ok := cache.Entries[file]; ok && entry.Size == info.Size() && entry.ModTime.Equal(info.ModTime()) {
+			fresh[file] = entry
+			continue
+		}
+
+		entry, err := readEntry(file, dateStr, info)
+		if err != nil {
+			continue
+		}
+		fresh[file] = entry
+		changed = true
+	}
+
+	// Deleted notes drop out of the cache too
+	if len(fresh) != len(cache.Entries) {
+		changed = true
+	}
+
+	if changed {
+		// A cache that can't be written only costs speed next time
+		writeCache(cacheFile{Version: version, Entries: fresh})
+	}
+
+	entries := make([]Entry, 0, len(fresh))
+	for _, entry := range fresh {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Date < entries[j].Date
+	})
+
+	return entries, nil
+}
+
+// Update refreshes the cached entry for a single note, typically right after
+// the editor saved it.
+func Update(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	dateStr := strings.TrimSuffix(filepath.Base(path), ".md")
+	if _, err := time.Parse("2006-01-02", dateStr); err != nil {
+		return nil
+	}
+
+	entry, err := readEntry(path, dateStr, info)
+	if err != nil {
+		return err
+	}
+
+	cache := readCache()
+	cache.Entries[path] = entry
+	return writeCache(cache)
+}
+
+func readEntry(path, dateStr string, info os.FileInfo) (Entry, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	text := string(content)
+	body := RemoveHTMLComments(text)
+
+	// Count words (excluding HTML comments for ghost text)
+	return Entry{
+		Path:      path,
+		Size:      info.Size(),
+		ModTime:   info.ModTime(),
+		Date:      dateStr,
+		Words:     len(strings.Fields(body)),
+		Prompt:    ExtractPrompt(text),
+		Mood:      ExtractMood(text),
+		Sentiment: sentiment.Score(body),
+		Language:  language.Analyze(body),
+	}, nil
+}
+
+func readCache() cacheFile {
+	empty := cacheFile{Version: version, Entries: make(map[string]Entry)}
+
+	path, err := cachePath()
+	if err != nil {
+		return empty
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return empty
+	}
+
+	var cache cacheFile
+	if err := json.Unmarshal(data, &cache); err != nil || cache.Version != version || cache.Entries == nil {
+		return empty
+	}
+	return cache
+}
+
+func writeCache(cache cacheFile) error {
+	path, err := cachePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temp file first so a concurrent reader never sees half a cache
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// ExtractPrompt returns the prompt stored in an entry's comment header,
+// skipping the date and mood lines the editor writes around it.
+func ExtractPrompt(text string) string {
+	var prompts []string
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "<!--") || !strings.HasSuffix(trimmed, "-->") {
+			continue
+		}
+		comment := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(trimmed, "<!--"), "-->"))
+		if _, err := time.Parse("Monday, January 2, 2006", comment); err == nil {
+			continue
+		}
+		if _, ok := mood.Parse(comment); ok {
+			continue
+		}
+		if comment != "" {
+			prompts = append(prompts, comment)
+		}
+	}
+	return strings.Join(prompts, " ")
+}
+
+// ExtractMood returns the mood stored in an entry's comment header, or 0 when
+// the entry wasn't rated.
+func ExtractMood(text string) int {
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "<!--") || !strings.HasSuffix(trimmed, "-->") {
+			continue
+		}
+		if rating, ok := mood.Parse(strings.TrimSuffix(strings.TrimPrefix(trimmed, "<!--"), "-->")); ok {
+			return rating
+		}
+	}
+	return 0
+}
+
+// RemoveHTMLComments strips <!-- --> comments, which hold the entry's date
+// and prompt rather than anything the writer typed.
+func RemoveHTMLComments(text string) string {
+	for {
+		start := strings.Index(text, "<!--")
+		if start == -1 {
+			break
+		}
+		end := strings.Index(text[start:], "-->")
+		if end == -1 {
+			break
+		}
+		text = text[:start] + text[start+end+3:]
+	}
+	return text
+}
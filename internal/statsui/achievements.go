@@ -0,0 +1,106 @@
+package statsui
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/mattwhite/river-go/internal/achievements"
+	"github.com/mattwhite/river-go/internal/streak"
+)
+
+// evaluateAchievements works out every milestone from the notes.
+func evaluateAchievements(s *stats, rules streak.Rules) {
+	days := make([]achievements.Day, 0, len(s.notes))
+	for _, note := range s.notes {
+		days = append(days, achievements.Day{Date: note.date, Words: note.words, TypingTime: note.typingTime})
+	}
+	s.achievements, s.bestDay = achievements.Evaluate(days, s.goals, rules, time.Now())
+}
+
+// recordAchievements stores milestones reached since they were last
+// recorded, so their dates stick.
+func recordAchievements(s *stats) {
+	store, err := achievements.Load()
+	if err != nil {
+		return
+	}
+	if len(store.Record(s.achievements, s.bestDay, time.Now())) > 0 {
+		store.Save()
+	}
+	s.unlocked = store.Unlocked
+}
+
+func (m Model) renderAchievements() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(highlight).
+		Bold(true)
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(subtle)
+
+	// Milestones are for all time, whatever range is shown
+	s := m.stats
+	if m.allStats != nil {
+		s = m.allStats
+	}
+
+	var earned, upcoming []achievements.Status
+	next := make(map[achievements.Metric]bool)
+	for _, status := range s.achievements {
+		switch {
+		case !status.Reached.IsZero():
+			earned = append(earned, status)
+		case !next[status.Metric]:
+			// Only the next target for each metric
+			next[status.Metric] = true
+			upcoming = append(upcoming, status)
+		}
+	}
+
+	// The date a milestone was stored wins over the replayed one
+	reachedOn := func(status achievements.Status) time.Time {
+		if dateStr, ok := s.unlocked[status.ID]; ok {
+			if date, err := time.Parse("2006-01-02", dateStr); err == nil {
+				return date
+			}
+		}
+		return status.Reached
+	}
+	sort.SliceStable(earned, func(i, j int) bool {
+		return reachedOn(earned[i]).After(reachedOn(earned[j]))
+	})
+	sort.SliceStable(upcoming, func(i, j int) bool {
+		return upcoming[i].Progress() > upcoming[j].Progress()
+	})
+
+	lines := []string{
+		titleStyle.Render("Achievements") +
+			labelStyle.Render(fmt.Sprintf("  %d of %d earned", len(earned), len(s.achievements))),
+	}
+	for _, status := range earned {
+		lines = append(lines, fmt.Sprintf("%s %-20s %s",
+			status.Icon, status.Name, labelStyle.Render(reachedOn(status).Format("Jan 2, 2006"))))
+	}
+	if s.bestDay.Words > 0 {
+		if date, err := time.Parse("2006-01-02", s.bestDay.Date); err == nil {
+			lines = append(lines, fmt.Sprintf("🏅 %-20s %s", "Personal best",
+				labelStyle.Render(fmt.Sprintf("%s words on %s", formatThousands(s.bestDay.Words), date.Format("Jan 2, 2006")))))
+		}
+	}
+
+	if len(upcoming) > 0 {
+		lines = append(lines, "", titleStyle.Render("Up next"))
+		for _, status := range upcoming {
+			lines = append(lines, fmt.Sprintf("%s %-20s %s %s",
+				status.Icon, status.Name, m.renderSparkBar(status.Value, status.Target, 20),
+				labelStyle.Render(fmt.Sprintf("%s / %s", formatThousands(status.Value), formatThousands(status.Target)))))
+		}
+	}
+
+	return lipgloss.NewStyle().
+		Padding(0, 1).
+		Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
@@ -0,0 +1,182 @@
+package statsui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/mattwhite/river-go/internal/editor"
+	"github.com/mattwhite/river-go/internal/statscache"
+)
+
+// dailyDates lists the days shown on the Daily tab, most recent first.
+func (m Model) dailyDates() []time.Time {
+	today := dateOnly(time.Now())
+	start, end := today.AddDate(0, 0, -30), today
+	if len(m.stats.notes) > 0 {
+		start = dateOnly(m.stats.notes[0].date)
+	}
+	if !m.period.isAllTime() {
+		start, end = m.period.start, m.period.end
+		if end.After(today) {
+			end = today
+		}
+	}
+
+	var dates []time.Time
+	for date := end; !date.Before(start); date = date.AddDate(0, 0, -1) {
+		dates = append(dates, date)
+	}
+	return dates
+}
+
+// dailySelection returns the day under the Daily tab's cursor.
+func (m Model) dailySelection() time.Time {
+	dates := m.dailyDates()
+	if len(dates) == 0 {
+		return dateOnly(time.Now())
+	}
+	return dates[min(m.dailyCursor, len(dates)-1)]
+}
+
+// hasNote reports whether anything was written on date.
+func (m Model) hasNote(date time.Time) bool {
+	return m.getWordsForDate(date) > 0
+}
+
+// noteFor returns the entry written on date.
+func (m Model) noteFor(date time.Time) (noteData, bool) {
+	note, ok := m.stats.byDate[date.Format("2006-01-02")]
+	return note, ok
+}
+
+// updateDaily moves the Daily tab's cursor and opens the selected day. It
+// reports whether msg was handled.
+func (m Model) updateDaily(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	vp := &m.viewports[tabDaily]
+	last := len(m.dailyDates()) - 1
+
+	switch {
+	case key.Matches(msg, keys.Up):
+		m.dailyCursor--
+	case key.Matches(msg, keys.Down):
+		m.dailyCursor++
+	case key.Matches(msg, keys.PageUp):
+		m.dailyCursor -= vp.Height
+	case key.Matches(msg, keys.PageDown):
+		m.dailyCursor += vp.Height
+	case key.Matches(msg, keys.Home):
+		m.dailyCursor = 0
+	case key.Matches(msg, keys.End):
+		m.dailyCursor = last
+	case key.Matches(msg, keys.Open):
+		m, cmd := m.openEditor(m.dailySelection())
+		return m, cmd, true
+	case key.Matches(msg, keys.Preview):
+		return m.openPreview(m.dailySelection()), nil, true
+	default:
+		return m, nil, false
+	}
+
+	m.dailyCursor = max(0, min(m.dailyCursor, last))
+
+	// Keep the cursor row on screen; there's one row per day
+	if m.dailyCursor < vp.YOffset {
+		vp.SetYOffset(m.dailyCursor)
+	} else if vp.Height > 0 && m.dailyCursor >= vp.YOffset+vp.Height {
+		vp.SetYOffset(m.dailyCursor - vp.Height + 1)
+	}
+	return m, nil, true
+}
+
+// openEditor hands the screen to the editor for date until it is closed.
+func (m Model) openEditor(date time.Time) (Model, tea.Cmd) {
+	m.previewing = false
+	m.editing = true
+	m.editor = editor.NewEmbeddedModel(date)
+
+	ed, cmd := m.editor.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+	m.editor = ed.(editor.Model)
+	return m, tea.Batch(m.editor.Init(), cmd)
+}
+
+// updateEditor passes msg to the embedded editor. Closing it reloads the
+// stats so the edit shows up straight away.
+func (m Model) updateEditor(msg tea.Msg) (Model, tea.Cmd) {
+	if _, ok := msg.(editor.ClosedMsg); ok {
+		m.editing = false
+		return m, loadStats
+	}
+
+	ed, cmd := m.editor.Update(msg)
+	m.editor = ed.(editor.Model)
+	return m, cmd
+}
+
+// openPreview shows the entry for date read-only. The entry is read once
+// here rather than on every render.
+func (m Model) openPreview(date time.Time) Model {
+	m.previewing = true
+	m.previewDate = date
+	m.preview = viewport.New(max(1, m.width-4), m.contentHeight())
+	m.previewText = ""
+	if data, err := os.ReadFile(editor.NotePath(date)); err == nil {
+		m.previewText = string(data)
+	}
+	return m
+}
+
+// updatePreview scrolls the preview. Enter switches to editing the entry.
+func (m Model) updatePreview(msg tea.Msg) (Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, keys.Open):
+			return m.openEditor(m.previewDate)
+		case key.Matches(msg, keys.Preview), key.Matches(msg, keys.Quit):
+			m.previewing = false
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.preview, cmd = m.preview.Update(msg)
+	return m, cmd
+}
+
+func (m Model) renderPreview() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(highlight).
+		Bold(true)
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(subtle)
+
+	title := titleStyle.Render(m.previewDate.Format("Monday, January 2, 2006"))
+
+	if m.previewText == "" || !m.hasNote(m.previewDate) {
+		return lipgloss.JoinVertical(lipgloss.Left,
+			title,
+			"",
+			labelStyle.Render("Nothing was written on this day. Press enter to backfill it."))
+	}
+
+	text := m.previewText
+	parts := []string{title}
+	if prompt := statscache.ExtractPrompt(text); prompt != "" {
+		parts = append(parts, labelStyle.Italic(true).Render("💭 "+prompt))
+	}
+	if note, ok := m.noteFor(m.previewDate); ok {
+		parts = append(parts, labelStyle.Render(fmt.Sprintf("%d words • %s", note.words, formatLanguage(note.language))))
+	}
+	parts = append(parts, "", lipgloss.NewStyle().
+		Width(min(m.width-6, 100)).
+		Render(strings.TrimSpace(statscache.RemoveHTMLComments(text))))
+
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
@@ -0,0 +1,237 @@
+package statsui
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type exportDay struct {
+	Date          string `json:"date"`
+	Words         int    `json:"words"`
+	TypingSeconds int    `json:"typing_seconds"`
+	GoalWords     int    `json:"goal_words"`
+	GoalMet       bool   `json:"goal_met"`
+	Status        string `json:"status"`
+	Streak        int    `json:"streak"`
+}
+
+type exportPeriod struct {
+	Start     string  `json:"start"`
+	Words     int     `json:"words"`
+	Days      int     `json:"days"`
+	Average   float64 `json:"average"`
+	GoalWords int     `json:"goal_words,omitempty"`
+	GoalMet   bool    `json:"goal_met"`
+}
+
+type exportToday struct {
+	Date          string `json:"date"`
+	Words         int    `json:"words"`
+	TypingSeconds int    `json:"typing_seconds"`
+	GoalWords     int    `json:"goal_words"`
+	GoalMinutes   int    `json:"goal_minutes"`
+	GoalMet       bool   `json:"goal_met"`
+}
+
+type exportStats struct {
+	GeneratedAt    string         `json:"generated_at"`
+	TotalWords     int            `json:"total_words"`
+	TotalDays      int            `json:"total_days"`
+	AverageWords   float64        `json:"average_words"`
+	TypingSeconds  int            `json:"typing_seconds"`
+	WordsPerMinute float64        `json:"words_per_minute"`
+	CurrentStreak  int            `json:"current_streak"`
+	LongestStreak  int            `json:"longest_streak"`
+	Today          exportToday    `json:"today"`
+	Daily          []exportDay    `json:"daily"`
+	Weekly         []exportPeriod `json:"weekly"`
+	Monthly        []exportPeriod `json:"monthly"`
+	Yearly         []exportPeriod `json:"yearly"`
+}
+
+// buildExport flattens the collected stats into their machine-readable form.
+func buildExport(s *stats) exportStats {
+	now := time.Now()
+	today := dateOnly(now)
+
+	out := exportStats{
+		GeneratedAt:    now.Format(time.RFC3339),
+		TotalWords:     s.totalWords,
+		TotalDays:      s.totalDays,
+		AverageWords:   round1(s.avgWords),
+		TypingSeconds:  int(s.totalTypingTime.Seconds()),
+		WordsPerMinute: round1(s.wordsPerMinute),
+		CurrentStreak:  s.currentStreak,
+		LongestStreak:  s.longestStreak,
+		Today: exportToday{
+			Date:          today.Format("2006-01-02"),
+			Words:         s.todayWords,
+			TypingSeconds: int(s.todayTypingTime.Seconds()),
+			GoalWords:     s.goals.WordsFor(today),
+			GoalMinutes:   int(s.goals.MinutesFor(today).Minutes()),
+			GoalMet:       s.goals.Met(today, s.todayWords, s.todayTypingTime),
+		},
+		Daily:   []exportDay{},
+		Weekly:  []exportPeriod{},
+		Monthly: []exportPeriod{},
+		Yearly:  []exportPeriod{},
+	}
+
+	// Every day from the first entry to today, including missed ones
+	if len(s.notes) > 0 {
+		noteMap := make(map[string]noteData)
+		for _, note := range s.notes {
+			noteMap[note.date.Format("2006-01-02")] = note
+		}
+
+		for date := s.notes[0].date; !date.After(today); date = date.AddDate(0, 0, 1) {
+			note := noteMap[date.Format("2006-01-02")]
+			out.Daily = append(out.Daily, exportDay{
+				Date:          date.Format("2006-01-02"),
+				Words:         note.words,
+				TypingSeconds: int(note.typingTime.Seconds()),
+				GoalWords:     s.goals.WordsFor(date),
+				GoalMet:       note.words > 0 && s.goals.Met(date, note.words, note.typingTime),
+				Status:        s.calendar.Status(date).String(),
+				Streak:        s.calendar.StreakOn(date),
+			})
+		}
+	}
+
+	for _, week := range s.weeklyData {
+		goal := weekGoal(s.goals, week.startDate, weekExpectedDays(week.startDate))
+		out.Weekly = append(out.Weekly, exportPeriod{
+			Start:     week.startDate.Format("2006-01-02"),
+			Words:     week.words,
+			Days:      week.days,
+			Average:   round1(week.avg),
+			GoalWords: goal,
+			GoalMet:   goal > 0 && week.words >= goal,
+		})
+	}
+
+	for _, month := range s.monthlyData {
+		start := time.Date(month.year, month.month, 1, 0, 0, 0, 0, time.UTC)
+		goal := rangeGoal(s.goals, start, start.AddDate(0, 1, -1))
+		out.Monthly = append(out.Monthly, exportPeriod{
+			Start:     start.Format("2006-01-02"),
+			Words:     month.words,
+			Days:      month.days,
+			Average:   round1(month.avg),
+			GoalWords: goal,
+			GoalMet:   goal > 0 && month.words >= goal,
+		})
+	}
+
+	for _, year := range s.yearlyData {
+		start := time.Date(year.year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		goal := rangeGoal(s.goals, start, start.AddDate(1, 0, -1))
+		out.Yearly = append(out.Yearly, exportPeriod{
+			Start:     start.Format("2006-01-02"),
+			Words:     year.words,
+			Days:      year.days,
+			Average:   round1(year.avg),
+			GoalWords: goal,
+			GoalMet:   goal > 0 && year.words >= goal,
+		})
+	}
+
+	return out
+}
+
+// WriteJSON writes the stats as a JSON document.
+func WriteJSON(w io.Writer) error {
+	s, err := collectStats()
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(buildExport(s))
+}
+
+// WriteCSV writes the stats as one CSV table. The period column tells the
+// daily, weekly, monthly and yearly rows apart; the streak column holds the
+// running streak for daily rows and the current streak for the total row.
+func WriteCSV(w io.Writer) error {
+	s, err := collectStats()
+	if err != nil {
+		return err
+	}
+	out := buildExport(s)
+
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"period", "start", "words", "days", "average", "typing_seconds", "goal_words", "goal_met", "status", "streak"})
+
+	for _, day := range out.Daily {
+		cw.Write([]string{"daily", day.Date, itoa(day.Words), "", "",
+			itoa(day.TypingSeconds), itoa(day.GoalWords), strconv.FormatBool(day.GoalMet),
+			day.Status, itoa(day.Streak)})
+	}
+
+	periods := []struct {
+		name string
+		rows []exportPeriod
+	}{
+		{"weekly", out.Weekly},
+		{"monthly", out.Monthly},
+		{"yearly", out.Yearly},
+	}
+	for _, period := range periods {
+		for _, row := range period.rows {
+			goal := ""
+			if row.GoalWords > 0 {
+				goal = itoa(row.GoalWords)
+			}
+			cw.Write([]string{period.name, row.Start, itoa(row.Words), itoa(row.Days),
+				strconv.FormatFloat(row.Average, 'f', 1, 64), "", goal,
+				strconv.FormatBool(row.GoalMet), "", ""})
+		}
+	}
+
+	cw.Write([]string{"total", "", itoa(out.TotalWords), itoa(out.TotalDays),
+		strconv.FormatFloat(out.AverageWords, 'f', 1, 64), itoa(out.TypingSeconds),
+		"", "", "", itoa(out.CurrentStreak)})
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteSummary writes a single status line such as "🔥 12 • 340/500".
+func WriteSummary(w io.Writer) error {
+	s, err := collectStats()
+	if err != nil {
+		return err
+	}
+
+	today := time.Now()
+	progress := fmt.Sprintf("%d", s.todayWords)
+	if goal := s.goals.WordsFor(today); goal > 0 {
+		progress = fmt.Sprintf("%d/%d", s.todayWords, goal)
+	} else if goal := s.goals.MinutesFor(today); goal > 0 {
+		progress = fmt.Sprintf("%d/%dm", int(s.todayTypingTime.Minutes()), int(goal.Minutes()))
+	}
+	if s.goals.Met(today, s.todayWords, s.todayTypingTime) {
+		progress += " ✓"
+	}
+
+	if s.currentStreak > 0 {
+		_, err = fmt.Fprintf(w, "🔥 %d • %s\n", s.currentStreak, progress)
+	} else {
+		_, err = fmt.Fprintln(w, progress)
+	}
+	return err
+}
+
+func itoa(n int) string {
+	return strconv.Itoa(n)
+}
+
+func round1(f float64) float64 {
+	return float64(int(f*10+0.5)) / 10
+}
@@ -0,0 +1,171 @@
+package statsui
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setupJournal makes a home directory with a 100-word daily goal and an entry
+// of the given length on each day, keyed by how many days ago it was.
+func setupJournal(t *testing.T, entries map[int]int) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	notes := filepath.Join(home, "river", "notes")
+	if err := os.MkdirAll(notes, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, "river", ".config"), []byte("DAILY_GOAL=100\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for ago, words := range entries {
+		date := time.Now().AddDate(0, 0, -ago).Format("2006-01-02")
+		if err := os.WriteFile(filepath.Join(notes, date+".md"), []byte(strings.Repeat("word ", words)), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// daysSoFar counts the days from start to end that have happened.
+func daysSoFar(start, end time.Time) int {
+	if today := dateOnly(time.Now()); end.After(today) {
+		end = today
+	}
+	return int(end.Sub(start).Hours()/24) + 1
+}
+
+func TestWriteSVGGoalLines(t *testing.T) {
+	setupJournal(t, map[int]int{1: 50, 0: 150})
+
+	tests := []struct {
+		chart string
+		bars  int
+	}{
+		{"weekly", svgWeeks},
+		{"monthly", svgMonths},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.chart, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := WriteSVG(&b, tt.chart, "light", 0); err != nil {
+				t.Fatal(err)
+			}
+			// Every bar has a goal, and two gridlines are dashed too
+			if got := strings.Count(b.String(), "stroke-dasharray"); got != tt.bars+2 {
+				t.Errorf("%d dashed lines, want a goal line on each of %d bars plus 2 gridlines", got, tt.bars)
+			}
+		})
+	}
+}
+
+func TestMonthlyBarsGoal(t *testing.T) {
+	setupJournal(t, map[int]int{0: 150})
+	s, err := collectStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	bars := monthlyBars(s)
+	for i, bar := range bars {
+		start := thisMonth.AddDate(0, i-len(bars)+1, 0)
+		if want := 100 * daysSoFar(start, start.AddDate(0, 1, -1)); bar.goal != want {
+			t.Errorf("%s goal = %d, want %d", start.Format("Jan 2006"), bar.goal, want)
+		}
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	setupJournal(t, map[int]int{1: 50, 0: 150})
+
+	var b bytes.Buffer
+	if err := WriteJSON(&b); err != nil {
+		t.Fatal(err)
+	}
+	var out exportStats
+	if err := json.Unmarshal(b.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if out.TotalWords != 200 || out.TotalDays != 2 || out.CurrentStreak != 2 {
+		t.Errorf("totals = %d words, %d days, streak %d; want 200, 2, 2", out.TotalWords, out.TotalDays, out.CurrentStreak)
+	}
+	if !out.Today.GoalMet || out.Today.Words != 150 || out.Today.GoalWords != 100 {
+		t.Errorf("today = %+v, want 150 of 100 words, met", out.Today)
+	}
+
+	wantDaily := []exportDay{
+		{Date: time.Now().AddDate(0, 0, -1).Format("2006-01-02"), Words: 50, GoalWords: 100, Status: "written", Streak: 1},
+		{Date: time.Now().Format("2006-01-02"), Words: 150, GoalWords: 100, GoalMet: true, Status: "written", Streak: 2},
+	}
+	if len(out.Daily) != len(wantDaily) {
+		t.Fatalf("daily rows = %+v, want %+v", out.Daily, wantDaily)
+	}
+	for i := range wantDaily {
+		if out.Daily[i] != wantDaily[i] {
+			t.Errorf("daily row %d = %+v, want %+v", i, out.Daily[i], wantDaily[i])
+		}
+	}
+
+	for _, month := range out.Monthly {
+		start, _ := time.Parse("2006-01-02", month.Start)
+		want := 100 * daysSoFar(start, start.AddDate(0, 1, -1))
+		if month.GoalWords != want || month.GoalMet != (month.Words >= want) {
+			t.Errorf("month %s = %d words of %d, met %v; want a goal of %d", month.Start, month.Words, month.GoalWords, month.GoalMet, want)
+		}
+	}
+	for _, year := range out.Yearly {
+		start, _ := time.Parse("2006-01-02", year.Start)
+		if want := 100 * daysSoFar(start, start.AddDate(1, 0, -1)); year.GoalWords != want {
+			t.Errorf("year %s goal = %d, want %d", year.Start, year.GoalWords, want)
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	setupJournal(t, map[int]int{1: 50, 0: 150})
+
+	var b bytes.Buffer
+	if err := WriteCSV(&b); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&b).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Join(rows[0], ","); got != "period,start,words,days,average,typing_seconds,goal_words,goal_met,status,streak" {
+		t.Errorf("header = %s", got)
+	}
+	counts := make(map[string]int)
+	for _, row := range rows[1:] {
+		counts[row[0]]++
+	}
+	if counts["daily"] != 2 || counts["weekly"] < 1 || counts["monthly"] < 1 || counts["yearly"] < 1 || counts["total"] != 1 {
+		t.Errorf("row counts = %v, want 2 daily, some of each period and 1 total", counts)
+	}
+	if total := rows[len(rows)-1]; total[0] != "total" || total[2] != "200" || total[9] != "2" {
+		t.Errorf("total row = %v, want 200 words and a streak of 2", total)
+	}
+}
+
+func TestWriteSummary(t *testing.T) {
+	setupJournal(t, map[int]int{1: 50, 0: 150})
+
+	var b bytes.Buffer
+	if err := WriteSummary(&b); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.String(); got != "🔥 2 • 150/100 ✓\n" {
+		t.Errorf("summary = %q", got)
+	}
+}
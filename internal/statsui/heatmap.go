@@ -0,0 +1,220 @@
+package statsui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/mattwhite/river-go/internal/config"
+	"github.com/mattwhite/river-go/internal/streak"
+)
+
+// heatmapLevels are the cell colors from "nothing written" to "goal met".
+var heatmapLevels = []lipgloss.AdaptiveColor{
+	{Light: "#EBEDF0", Dark: "#2D333B"},
+	{Light: "#9BE9A8", Dark: "#0E4429"},
+	{Light: "#40C463", Dark: "#006D32"},
+	{Light: "#30A14E", Dark: "#26A641"},
+	{Light: "#216E39", Dark: "#39D353"},
+}
+
+// heatmapLevel buckets words relative to the goal for the day.
+func heatmapLevel(words, goal int) int {
+	if words <= 0 {
+		return 0
+	}
+	if goal <= 0 {
+		goal = config.DefaultDailyGoal
+	}
+	switch ratio := float64(words) / float64(goal); {
+	case ratio >= 1:
+		return 4
+	case ratio >= 0.5:
+		return 3
+	case ratio >= 0.25:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// updateHeatmap moves the heatmap cursor. It reports whether msg was handled.
+func (m Model) updateHeatmap(msg tea.KeyMsg) (Model, bool) {
+	switch {
+	case key.Matches(msg, keys.Left):
+		m.heatmapCursor = m.heatmapCursor.AddDate(0, 0, -7)
+	case key.Matches(msg, keys.Right):
+		m.heatmapCursor = m.heatmapCursor.AddDate(0, 0, 7)
+	case key.Matches(msg, keys.Up):
+		m.heatmapCursor = m.heatmapCursor.AddDate(0, 0, -1)
+	case key.Matches(msg, keys.Down):
+		m.heatmapCursor = m.heatmapCursor.AddDate(0, 0, 1)
+	case key.Matches(msg, keys.PrevYear):
+		m.heatmapCursor = m.heatmapCursor.AddDate(-1, 0, 0)
+	case key.Matches(msg, keys.NextYear):
+		m.heatmapCursor = m.heatmapCursor.AddDate(1, 0, 0)
+	default:
+		return m, false
+	}
+
+	// Don't wander into the future
+	if today := dateOnly(time.Now()); m.heatmapCursor.After(today) {
+		m.heatmapCursor = today
+	}
+	return m, true
+}
+
+func (m Model) renderHeatmap() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(highlight).
+		Bold(true)
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(subtle)
+
+	year := m.heatmapCursor.Year()
+	today := dateOnly(time.Now())
+
+	noteMap := m.stats.byDate
+	yearWords, yearDays := 0, 0
+	for _, note := range m.stats.notes {
+		if note.date.Year() == year {
+			yearWords += note.words
+			yearDays++
+		}
+	}
+
+	// Use wide cells when the whole year fits
+	cellWidth := 1
+	if m.width-10 >= 53*2 {
+		cellWidth = 2
+	}
+
+	// The grid starts on the Sunday on or before January 1st
+	jan1 := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	gridStart := jan1.AddDate(0, 0, -int(jan1.Weekday()))
+	dec31 := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	weeks := int(dec31.Sub(gridStart).Hours()/24)/7 + 1
+
+	// Month labels above the first week that contains the 1st of each month
+	monthRow := []rune(strings.Repeat(" ", weeks*cellWidth+1))
+	for month := time.January; month <= time.December; month++ {
+		first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+		col := int(first.Sub(gridStart).Hours()/24) / 7 * cellWidth
+		for i, r := range first.Format("Jan") {
+			if col+i < len(monthRow) {
+				monthRow[col+i] = r
+			}
+		}
+	}
+
+	rows := []string{"    " + labelStyle.Render(string(monthRow))}
+	dayLabels := []string{"", "Mon", "", "Wed", "", "Fri", ""}
+	cursorStyle := lipgloss.NewStyle().Foreground(highlight).Bold(true)
+
+	for weekday := 0; weekday < 7; weekday++ {
+		var row strings.Builder
+		row.WriteString(labelStyle.Render(fmt.Sprintf("%-4s", dayLabels[weekday])))
+
+		for week := 0; week < weeks; week++ {
+			date := gridStart.AddDate(0, 0, week*7+weekday)
+			cell := "■"
+			if cellWidth == 2 {
+				cell = "■ "
+			}
+
+			switch {
+			case date.Year() != year || date.After(today):
+				row.WriteString(strings.Repeat(" ", cellWidth))
+				continue
+			case date.Equal(m.heatmapCursor):
+				cell = "◆" + cell[len("■"):]
+				row.WriteString(cursorStyle.Render(cell))
+				continue
+			}
+
+			color := heatmapLevels[0]
+			if note, ok := noteMap[date.Format("2006-01-02")]; ok {
+				color = heatmapLevels[heatmapLevel(note.words, m.stats.goals.WordsFor(date))]
+			} else if m.stats.calendar.Status(date) == streak.Frozen {
+				row.WriteString(lipgloss.NewStyle().Foreground(frozen).Render(cell))
+				continue
+			}
+			row.WriteString(lipgloss.NewStyle().Foreground(color).Render(cell))
+		}
+		rows = append(rows, row.String())
+	}
+
+	// Legend
+	var legend strings.Builder
+	legend.WriteString(labelStyle.Render("    Less "))
+	for _, color := range heatmapLevels {
+		legend.WriteString(lipgloss.NewStyle().Foreground(color).Render("■ "))
+	}
+	legend.WriteString(labelStyle.Render("More (relative to goal)"))
+
+	title := titleStyle.Render(fmt.Sprintf("%d", year)) +
+		labelStyle.Render(fmt.Sprintf("  %s words in %d days", formatNumber(yearWords), yearDays))
+
+	sections := []string{
+		title,
+		"",
+		strings.Join(rows, "\n"),
+		"",
+		legend.String(),
+		"",
+		m.renderHeatmapSelection(noteMap),
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// renderHeatmapSelection describes the day under the cursor.
+func (m Model) renderHeatmapSelection(noteMap map[string]noteData) string {
+	date := m.heatmapCursor
+	dateStr := lipgloss.NewStyle().
+		Foreground(highlight).
+		Bold(true).
+		Render(date.Format("Monday, January 2, 2006"))
+
+	note, ok := noteMap[date.Format("2006-01-02")]
+	if !ok {
+		status := "No entry"
+		switch m.stats.calendar.Status(date) {
+		case streak.Rest:
+			status = "Rest day"
+		case streak.Frozen:
+			status = "❄ Streak freeze"
+		}
+		return lipgloss.JoinVertical(lipgloss.Left,
+			dateStr,
+			lipgloss.NewStyle().Foreground(subtle).Render(status))
+	}
+
+	words := fmt.Sprintf("%d words", note.words)
+	if goal := m.stats.goals.WordsFor(date); goal > 0 {
+		words = fmt.Sprintf("%d / %d words", note.words, goal)
+	}
+	if m.stats.goals.Met(date, note.words, note.typingTime) {
+		words += " ✓"
+	}
+
+	lines := []string{dateStr, words}
+	if note.prompt != "" {
+		lines = append(lines, lipgloss.NewStyle().
+			Foreground(subtle).
+			Width(min(m.width-6, 80)).
+			Render("💭 "+note.prompt))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// dateOnly returns t's calendar date at midnight UTC, matching note dates.
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,110 @@
+package statsui
+
+import (
+	"context"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/mattwhite/river-go/internal/ai"
+)
+
+type insightsMsg struct {
+	text   string
+	err    error
+	period period // The range the analysis covers
+}
+
+// aggregate converts the collected stats into the form the AI prompt expects.
+func (s *stats) aggregate() ai.AggregatedStats {
+	agg := ai.AggregatedStats{
+		TotalWords:      s.totalWords,
+		TotalTypingTime: s.totalTypingTime,
+		TotalDays:       s.totalDays,
+		CurrentStreak:   s.currentStreak,
+		LongestStreak:   s.longestStreak,
+		AverageWords:    int(s.avgWords),
+	}
+
+	for _, note := range s.notes {
+		if note.words > agg.MostProductiveWords {
+			agg.MostProductiveWords = note.words
+			agg.MostProductiveDay = note.date.Format("Monday, January 2, 2006")
+		}
+		agg.DailyStats = append(agg.DailyStats, ai.DailyStat{
+			Date:       note.date,
+			Words:      note.words,
+			TypingTime: note.typingTime,
+		})
+	}
+
+	return agg
+}
+
+// fetchInsights asks the AI for an analysis of the stats and the notes from
+// the same period; all time reads the last week of notes. Unless refresh is
+// set, an analysis of the same stats and notes is reused from the AI reply
+// cache, which saves spending tokens on every visit.
+func fetchInsights(ctx context.Context, s *stats, refresh bool) tea.Cmd {
+	agg := s.aggregate()
+	p := s.period
+	opts := ai.Options{Fresh: refresh}
+	if !p.isAllTime() {
+		opts.Window = ai.Window{Since: p.start, Until: p.end}
+	}
+	return func() tea.Msg {
+		text, err := ai.RequestStatsInsights(ctx, agg, p.key(), opts)
+		if err != nil {
+			return insightsMsg{err: err, period: p}
+		}
+		return insightsMsg{text: text, period: p}
+	}
+}
+
+// startInsights fetches an analysis in the background.
+func (m Model) startInsights(refresh bool) (Model, tea.Cmd) {
+	if m.insightsLoading || m.stats == nil {
+		return m, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.insightsLoading = true
+	m.insightsErr = nil
+	m.insightsCancel = cancel
+	return m, tea.Batch(m.spinner.Tick, fetchInsights(ctx, m.stats, refresh))
+}
+
+// stopInsights cancels the analysis being fetched, if any.
+func (m Model) stopInsights() Model {
+	if m.insightsCancel != nil {
+		m.insightsCancel()
+		m.insightsCancel = nil
+	}
+	return m
+}
+
+func (m Model) renderInsights() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(subtle)
+
+	switch {
+	case m.insightsLoading:
+		return m.spinner.View() + labelStyle.Render(" Analyzing your writing habits...")
+	case m.insightsErr != nil:
+		msg := m.insightsErr.Error()
+		if strings.Contains(msg, "No API key found") {
+			msg = "AI insights need an API key. Run 'river onboard' to set one up."
+		}
+		return lipgloss.JoinVertical(lipgloss.Left,
+			lipgloss.NewStyle().Foreground(warning).Render("✗ "+msg),
+			"",
+			labelStyle.Render("Press r to try again."))
+	case m.insights == "":
+		return labelStyle.Render("Press r to generate insights from your writing stats.")
+	}
+
+	return lipgloss.NewStyle().
+		Width(min(m.width-6, 100)).
+		Render(strings.TrimSpace(m.insights))
+}
@@ -0,0 +1,89 @@
+package statsui
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fetchCmd runs cmd, a batch from startInsights, and returns a channel with
+// the analysis it fetches.
+func fetchCmd(cmd tea.Cmd) <-chan tea.Msg {
+	done := make(chan tea.Msg, 1)
+	batch, _ := cmd().(tea.BatchMsg)
+	for _, c := range batch {
+		go func() {
+			if msg, ok := c().(insightsMsg); ok {
+				done <- msg
+			}
+		}()
+	}
+	return done
+}
+
+func TestInsightsRetryAfterLeavingTab(t *testing.T) {
+	// The AI server never answers, so the only way out is cancelling
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.MkdirAll(filepath.Join(home, "river", "notes"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := "AI_PROVIDER=ollama\nAI_BASE_URL=" + server.URL + "\nAI_MODEL=test\n"
+	if err := os.WriteFile(filepath.Join(home, "river", ".config"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := InitModel()
+	m.loading = false
+	m.stats = &stats{period: allTime()}
+
+	m.activeTab = tabInsights
+	m, cmd := m.enterTab()
+	if !m.insightsLoading || cmd == nil {
+		t.Fatal("opening the Insights tab didn't start an analysis")
+	}
+	fetched := fetchCmd(cmd)
+
+	m.activeTab = tabOverview
+	m, _ = m.enterTab()
+
+	var msg tea.Msg
+	select {
+	case msg = <-fetched:
+	case <-time.After(5 * time.Second):
+		t.Fatal("leaving the tab didn't cancel the analysis")
+	}
+	if err := msg.(insightsMsg).err; !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled analysis failed with %v, want context.Canceled", err)
+	}
+
+	updated, _ := m.Update(msg)
+	m = updated.(Model)
+	if m.insightsErr != nil || m.insightsLoading {
+		t.Fatalf("after cancelling: error %v, loading %v; want neither", m.insightsErr, m.insightsLoading)
+	}
+
+	m.activeTab = tabInsights
+	m, cmd = m.enterTab()
+	if !m.insightsLoading || cmd == nil {
+		t.Error("coming back to the Insights tab didn't ask again")
+	}
+	m.stopInsights()
+}
@@ -0,0 +1,180 @@
+package statsui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/mattwhite/river-go/internal/language"
+)
+
+// languageBucket holds the language totals for one week or month.
+type languageBucket struct {
+	label   string
+	metrics language.Metrics
+	ratio   float64 // Mean of the entries' unique-word ratios
+	entries int
+}
+
+// languageBuckets groups notes by week for short periods and by month
+// otherwise, oldest first.
+func languageBuckets(notes []noteData, weekly bool) []languageBucket {
+	var buckets []languageBucket
+	for _, note := range notes {
+		if note.language.Words == 0 {
+			continue
+		}
+
+		label := note.date.Format("Jan 2006")
+		if weekly {
+			weekStart := note.date
+			for weekStart.Weekday() != time.Sunday {
+				weekStart = weekStart.AddDate(0, 0, -1)
+			}
+			label = "Week " + weekStart.Format("Jan 2")
+		}
+
+		if len(buckets) == 0 || buckets[len(buckets)-1].label != label {
+			buckets = append(buckets, languageBucket{label: label})
+		}
+		b := &buckets[len(buckets)-1]
+		b.metrics = b.metrics.Add(note.language)
+		b.ratio += note.language.UniqueRatio()
+		b.entries++
+	}
+
+	for i := range buckets {
+		buckets[i].ratio /= float64(buckets[i].entries)
+	}
+	return buckets
+}
+
+// formatLanguage summarizes one entry's metrics on a single line.
+func formatLanguage(metrics language.Metrics) string {
+	if metrics.Words == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.0f%% unique • %.1f words/sentence • readability %.0f (%s)",
+		metrics.UniqueRatio()*100, metrics.SentenceLength(),
+		metrics.Readability(), language.ReadabilityLabel(metrics.Readability()))
+}
+
+func (m Model) renderLanguage() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(highlight).
+		Bold(true)
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(subtle)
+
+	valueStyle := lipgloss.NewStyle().
+		Foreground(highlight)
+
+	var total language.Metrics
+	ratios, entries := 0.0, 0
+	var docs []map[string]int
+	for _, note := range m.stats.notes {
+		if note.language.Words == 0 {
+			continue
+		}
+		total = total.Add(note.language)
+		ratios += note.language.UniqueRatio()
+		entries++
+		docs = append(docs, note.language.Terms)
+	}
+	if entries == 0 {
+		return labelStyle.Render("No entries yet. Language metrics appear here once you write.")
+	}
+
+	sections := []string{
+		titleStyle.Render("Language") + labelStyle.Render(fmt.Sprintf("  across %d entries", entries)),
+		fmt.Sprintf("%s %s  •  %s %s  •  %s %s",
+			valueStyle.Render(fmt.Sprintf("%.0f%%", ratios/float64(entries)*100)),
+			labelStyle.Render("unique words"),
+			valueStyle.Render(fmt.Sprintf("%.1f", total.SentenceLength())),
+			labelStyle.Render("words/sentence"),
+			valueStyle.Render(fmt.Sprintf("%.0f", total.Readability())),
+			labelStyle.Render("readability ("+language.ReadabilityLabel(total.Readability())+")")),
+		"",
+	}
+
+	// Trends, by week for short ranges so there's more than one point
+	weekly := !m.period.isAllTime() && m.period.days() <= 90
+	buckets := languageBuckets(m.stats.notes, weekly)
+	title := "By month"
+	if weekly {
+		title = "By week"
+	}
+
+	rows := []string{
+		titleStyle.Render(title),
+		labelStyle.Render(fmt.Sprintf("%-12s %8s %10s %12s", "", "unique", "sentence", "readability")),
+	}
+	ratioSeries := make([]float64, len(buckets))
+	lengthSeries := make([]float64, len(buckets))
+	readSeries := make([]float64, len(buckets))
+	for i, b := range buckets {
+		ratioSeries[i] = b.ratio
+		lengthSeries[i] = b.metrics.SentenceLength()
+		readSeries[i] = b.metrics.Readability()
+	}
+	for i := len(buckets) - 1; i >= 0; i-- {
+		b := buckets[i]
+		rows = append(rows, fmt.Sprintf("%-12s %7.0f%% %10.1f %12.0f",
+			b.label, b.ratio*100, b.metrics.SentenceLength(), b.metrics.Readability()))
+	}
+	sections = append(sections, strings.Join(rows, "\n"), "")
+
+	if len(buckets) > 1 {
+		lo, hi := seriesRange(lengthSeries)
+		sections = append(sections,
+			titleStyle.Render("Trend"),
+			fmt.Sprintf("%-12s %s", "Unique", valueStyle.Render(sparkline(ratioSeries, 0, 1))),
+			fmt.Sprintf("%-12s %s", "Sentence", valueStyle.Render(sparkline(lengthSeries, lo, hi))),
+			fmt.Sprintf("%-12s %s", "Readability", valueStyle.Render(sparkline(readSeries, 0, 100))),
+			labelStyle.Render(fmt.Sprintf("%-12s %s → %s", "", buckets[0].label, buckets[len(buckets)-1].label)),
+			"")
+	}
+
+	// Topic words stand out against everything ever written
+	corpusNotes := m.stats.notes
+	if m.allStats != nil {
+		corpusNotes = m.allStats.notes
+	}
+	var corpus []map[string]int
+	for _, note := range corpusNotes {
+		if len(note.language.Terms) > 0 {
+			corpus = append(corpus, note.language.Terms)
+		}
+	}
+
+	terms := language.TopTerms(docs, corpus, 12)
+	if len(terms) > 0 {
+		topics := []string{titleStyle.Render("Topic words")}
+		for _, term := range terms {
+			bar := m.renderSparkBar(int(term.Score*1000), int(terms[0].Score*1000), 15)
+			topics = append(topics, fmt.Sprintf("%-16s %s %s", term.Word, bar,
+				labelStyle.Render(fmt.Sprintf("%d×", term.Count))))
+		}
+		sections = append(sections, strings.Join(topics, "\n"))
+	}
+
+	return strings.Join(sections, "\n")
+}
+
+// seriesRange returns the smallest and largest values, widened slightly so a
+// flat series still draws.
+func seriesRange(values []float64) (float64, float64) {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	if hi-lo < 1 {
+		lo, hi = lo-0.5, hi+0.5
+	}
+	return lo, hi
+}
@@ -0,0 +1,273 @@
+package statsui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+type yearData struct {
+	year  int
+	words int
+	days  int
+	avg   float64
+}
+
+func calculateYearlyData(notes []noteData) []yearData {
+	yearMap := make(map[int]*yearData)
+
+	for _, note := range notes {
+		year := note.date.Year()
+		if y, exists := yearMap[year]; exists {
+			y.words += note.words
+			y.days++
+		} else {
+			yearMap[year] = &yearData{
+				year:  year,
+				words: note.words,
+				days:  1,
+			}
+		}
+	}
+
+	years := []yearData{}
+	for _, y := range yearMap {
+		y.avg = float64(y.words) / float64(y.days)
+		years = append(years, *y)
+	}
+
+	sort.Slice(years, func(i, j int) bool {
+		return years[i].year < years[j].year
+	})
+
+	return years
+}
+
+// yearRange returns the first and last years that can be selected.
+func (m Model) yearRange() (int, int) {
+	last := time.Now().Year()
+	if len(m.stats.notes) == 0 {
+		return last, last
+	}
+	return m.stats.notes[0].date.Year(), last
+}
+
+func (m Model) renderMonthly() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(highlight).
+		Bold(true)
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(subtle)
+
+	year := m.selectedYear
+	months := make(map[time.Month]monthData)
+	prevDecember := monthData{}
+	for _, month := range m.stats.monthlyData {
+		if month.year == year {
+			months[month.month] = month
+		} else if month.year == year-1 && month.month == time.December {
+			prevDecember = month
+		}
+	}
+
+	maxWords := 0
+	for _, month := range months {
+		maxWords = max(maxWords, month.words)
+	}
+
+	lines := []string{}
+	prev := prevDecember
+	var best, worst *monthData
+	total, activeMonths := 0, 0
+	now := time.Now()
+
+	for month := time.January; month <= time.December; month++ {
+		if year == now.Year() && month > now.Month() {
+			break
+		}
+
+		data, ok := months[month]
+		if !ok {
+			lines = append(lines, labelStyle.Render(fmt.Sprintf("%-4s %s", month.String()[:3], strings.Repeat("─", 20))))
+			prev = monthData{}
+			continue
+		}
+
+		bar := m.renderSparkBar(data.words, maxWords, 20)
+		line := fmt.Sprintf("%-4s %s %7s words • %4.0f/day • %2d days  %s",
+			month.String()[:3], bar, formatThousands(data.words), data.avg, data.days,
+			formatChange(data.words, prev.words))
+
+		lineStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+		start := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+		if goal := rangeGoal(m.stats.goals, start, start.AddDate(0, 1, -1)); goal > 0 && data.words >= goal {
+			lineStyle = lineStyle.Foreground(special)
+		}
+		lines = append(lines, lineStyle.Render(line))
+
+		d := data
+		if best == nil || d.words > best.words {
+			best = &d
+		}
+		if worst == nil || d.words < worst.words {
+			worst = &d
+		}
+		total += data.words
+		activeMonths++
+		prev = data
+	}
+
+	sections := []string{
+		titleStyle.Render(fmt.Sprintf("%d by month", year)),
+		"",
+		strings.Join(lines, "\n"),
+	}
+
+	if activeMonths > 0 {
+		summary := []string{
+			fmt.Sprintf("Average: %s words/month", formatThousands(total/activeMonths)),
+			fmt.Sprintf("Best:    %s %d (%s words)", best.month, best.year, formatThousands(best.words)),
+			fmt.Sprintf("Worst:   %s %d (%s words)", worst.month, worst.year, formatThousands(worst.words)),
+		}
+		// Compare like with like: a year in progress against the same part
+		// of the one before
+		if current, previous, ok := m.yearToDate(year); ok {
+			summary = append(summary, fmt.Sprintf("vs %d: %s → %s words %s",
+				year-1, formatThousands(previous), formatThousands(current), formatChange(current, previous)))
+		}
+		sections = append(sections, "", labelStyle.Render(strings.Join(summary, "\n")))
+	}
+
+	sections = append(sections, "",
+		titleStyle.Render("Writing Patterns"),
+		labelStyle.MarginLeft(2).Render(m.analyzePatterns()))
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+func (m Model) renderYearly() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(highlight).
+		Bold(true)
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(subtle)
+
+	if len(m.stats.yearlyData) == 0 {
+		return labelStyle.Render("No entries yet")
+	}
+
+	maxWords := 0
+	for _, y := range m.stats.yearlyData {
+		maxWords = max(maxWords, y.words)
+	}
+
+	lines := []string{}
+	prev := yearData{}
+	best, worst := m.stats.yearlyData[0], m.stats.yearlyData[0]
+	total := 0
+
+	for _, y := range m.stats.yearlyData {
+		bar := m.renderSparkBar(y.words, maxWords, 20)
+		line := fmt.Sprintf("%d %s %8s words • %4.0f/day • %3d days  %s",
+			y.year, bar, formatThousands(y.words), y.avg, y.days,
+			formatChange(y.words, prev.words))
+
+		lineStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+		if y.year == m.selectedYear {
+			lineStyle = lineStyle.Foreground(highlight).Bold(true)
+			line = "▸ " + line
+		} else {
+			line = "  " + line
+		}
+		lines = append(lines, lineStyle.Render(line))
+
+		if y.words > best.words {
+			best = y
+		}
+		if y.words < worst.words {
+			worst = y
+		}
+		total += y.words
+		prev = y
+	}
+
+	summary := []string{
+		fmt.Sprintf("Average: %s words/year", formatThousands(total/len(m.stats.yearlyData))),
+		fmt.Sprintf("Best:    %d (%s words)", best.year, formatThousands(best.words)),
+		fmt.Sprintf("Worst:   %d (%s words)", worst.year, formatThousands(worst.words)),
+	}
+
+	// Compare the selected year with the same stretch of the year before,
+	// so a year in progress isn't measured against a complete one
+	if current, previous, ok := m.yearToDate(m.selectedYear); ok {
+		summary = append(summary, fmt.Sprintf("%d vs %d to date: %s → %s words %s",
+			m.selectedYear, m.selectedYear-1,
+			formatThousands(previous), formatThousands(current), formatChange(current, previous)))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		titleStyle.Render("By year"),
+		"",
+		strings.Join(lines, "\n"),
+		"",
+		labelStyle.Render(strings.Join(summary, "\n")),
+	)
+}
+
+// yearToDate sums the words written in year and in the previous year up to
+// the same day of the year. Past years are compared in full.
+func (m Model) yearToDate(year int) (current, previous int, ok bool) {
+	cutoff := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	if now := time.Now(); year == now.Year() {
+		cutoff = dateOnly(now)
+	}
+	prevCutoff := cutoff.AddDate(-1, 0, 0)
+
+	for _, note := range m.stats.notes {
+		switch note.date.Year() {
+		case year:
+			if !note.date.After(cutoff) {
+				current += note.words
+			}
+		case year - 1:
+			if !note.date.After(prevCutoff) {
+				previous += note.words
+				ok = true
+			}
+		}
+	}
+	return current, previous, ok
+}
+
+// formatChange renders the change from previous to current as a percentage.
+func formatChange(current, previous int) string {
+	if previous == 0 {
+		return ""
+	}
+	change := float64(current-previous) / float64(previous) * 100
+	style := lipgloss.NewStyle().Foreground(special)
+	sign := "▲"
+	if change < 0 {
+		style = lipgloss.NewStyle().Foreground(warning)
+		sign = "▼"
+		change = -change
+	}
+	return style.Render(fmt.Sprintf("%s %.0f%%", sign, change))
+}
+
+// formatThousands renders n with thousands separators.
+func formatThousands(n int) string {
+	s := fmt.Sprintf("%d", n)
+	if n < 0 {
+		return "-" + formatThousands(-n)
+	}
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
@@ -0,0 +1,219 @@
+package statsui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/mattwhite/river-go/internal/editor"
+)
+
+// sparkLevels draw a value as one of eight bar heights.
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws values between lo and hi. Missing values (NaN) are blank.
+func sparkline(values []float64, lo, hi float64) string {
+	var b strings.Builder
+	for _, v := range values {
+		if math.IsNaN(v) {
+			b.WriteRune(' ')
+			continue
+		}
+		level := int((v - lo) / (hi - lo) * float64(len(sparkLevels)-1))
+		b.WriteRune(sparkLevels[max(0, min(level, len(sparkLevels)-1))])
+	}
+	return b.String()
+}
+
+// correlation returns the Pearson correlation of xs and ys, or NaN when there
+// are too few points or no variation.
+func correlation(xs, ys []float64) float64 {
+	n := float64(len(xs))
+	if len(xs) < 3 {
+		return math.NaN()
+	}
+
+	var sumX, sumY float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+	}
+	meanX, meanY := sumX/n, sumY/n
+
+	var cov, varX, varY float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return math.NaN()
+	}
+	return cov / math.Sqrt(varX*varY)
+}
+
+// describeCorrelation puts r into words.
+func describeCorrelation(r float64) string {
+	if math.IsNaN(r) {
+		return "not enough data"
+	}
+
+	strength := "no clear link"
+	switch a := math.Abs(r); {
+	case a >= 0.5:
+		strength = "strong"
+	case a >= 0.3:
+		strength = "moderate"
+	case a >= 0.1:
+		strength = "weak"
+	}
+	if strength == "no clear link" {
+		return fmt.Sprintf("%s (r = %+.2f)", strength, r)
+	}
+
+	direction := "positive"
+	if r < 0 {
+		direction = "negative"
+	}
+	return fmt.Sprintf("%s %s (r = %+.2f)", strength, direction, r)
+}
+
+// padLeft right-aligns s in a column of width cells. Unlike fmt it counts
+// emoji and styled text by their width on screen.
+func padLeft(s string, width int) string {
+	return strings.Repeat(" ", max(0, width-lipgloss.Width(s))) + s
+}
+
+func formatSentiment(s float64) string {
+	style := lipgloss.NewStyle().Foreground(subtle)
+	switch {
+	case s >= 0.1:
+		style = style.Foreground(special)
+	case s <= -0.1:
+		style = style.Foreground(warning)
+	}
+	return style.Render(fmt.Sprintf("%+.2f", s))
+}
+
+func (m Model) renderMood() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(highlight).
+		Bold(true)
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(subtle)
+
+	notes := []noteData{}
+	for _, note := range m.stats.notes {
+		if note.words > 0 {
+			notes = append(notes, note)
+		}
+	}
+	if len(notes) == 0 {
+		return labelStyle.Render("No entries yet. Mood and sentiment appear here once you write.")
+	}
+
+	// Averages, and the points for the correlations
+	var words, moods, sentiments, ratedWords, ratedSentiments []float64
+	moodTotal := 0
+	for _, note := range notes {
+		words = append(words, float64(note.words))
+		sentiments = append(sentiments, note.sentiment)
+		if note.mood > 0 {
+			moods = append(moods, float64(note.mood))
+			ratedWords = append(ratedWords, float64(note.words))
+			ratedSentiments = append(ratedSentiments, note.sentiment)
+			moodTotal += note.mood
+		}
+	}
+	sentimentTotal := 0.0
+	for _, s := range sentiments {
+		sentimentTotal += s
+	}
+
+	summary := fmt.Sprintf("Average sentiment %s across %d entries",
+		formatSentiment(sentimentTotal/float64(len(notes))), len(notes))
+	if len(moods) > 0 {
+		avgMood := float64(moodTotal) / float64(len(moods))
+		summary = fmt.Sprintf("Average mood %.1f %s from %d ratings • ",
+			avgMood, editor.MoodFaces[int(avgMood+0.5)-1], len(moods)) + summary
+	}
+
+	sections := []string{
+		titleStyle.Render("Mood & Sentiment"),
+		labelStyle.Render(summary),
+		"",
+	}
+
+	// Both series over time, one column per entry, most recent on the right
+	width := max(10, min(m.width-20, 80))
+	recent := notes[max(0, len(notes)-width):]
+	moodSeries := make([]float64, len(recent))
+	sentimentSeries := make([]float64, len(recent))
+	for i, note := range recent {
+		moodSeries[i] = math.NaN()
+		if note.mood > 0 {
+			moodSeries[i] = float64(note.mood)
+		}
+		sentimentSeries[i] = note.sentiment
+	}
+	sections = append(sections,
+		titleStyle.Render("Over time"),
+		fmt.Sprintf("%-10s %s", "Mood", lipgloss.NewStyle().Foreground(highlight).Render(sparkline(moodSeries, 1, 5))),
+		fmt.Sprintf("%-10s %s", "Sentiment", lipgloss.NewStyle().Foreground(special).Render(sparkline(sentimentSeries, -1, 1))),
+		labelStyle.Render(fmt.Sprintf("%-10s %s → %s", "", recent[0].date.Format("Jan 2"), recent[len(recent)-1].date.Format("Jan 2"))),
+		"",
+	)
+
+	// What moves together
+	sections = append(sections,
+		titleStyle.Render("Correlations"),
+		fmt.Sprintf("%-22s %s", "Mood ↔ words", describeCorrelation(correlation(moods, ratedWords))),
+		fmt.Sprintf("%-22s %s", "Sentiment ↔ words", describeCorrelation(correlation(sentiments, words))),
+		fmt.Sprintf("%-22s %s", "Mood ↔ sentiment", describeCorrelation(correlation(moods, ratedSentiments))),
+		"",
+	)
+
+	// By day of week
+	type dayTotals struct {
+		entries, words, rated, mood int
+		sentiment                   float64
+	}
+	var byDay [7]dayTotals
+	for _, note := range notes {
+		d := &byDay[note.date.Weekday()]
+		d.entries++
+		d.words += note.words
+		d.sentiment += note.sentiment
+		if note.mood > 0 {
+			d.rated++
+			d.mood += note.mood
+		}
+	}
+
+	sections = append(sections,
+		titleStyle.Render("By day of week"),
+		labelStyle.Render(padLeft("", 4)+padLeft("mood", 8)+padLeft("sentiment", 11)+padLeft("words", 8)))
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		d := byDay[day]
+		name := fmt.Sprintf("%-4s", day.String()[:3])
+		if d.entries == 0 {
+			sections = append(sections, labelStyle.Render(name+padLeft("—", 8)+padLeft("—", 11)+padLeft("—", 8)))
+			continue
+		}
+		mood := "—"
+		if d.rated > 0 {
+			avg := float64(d.mood) / float64(d.rated)
+			mood = fmt.Sprintf("%.1f %s", avg, editor.MoodFaces[int(avg+0.5)-1])
+		}
+		sections = append(sections, name+padLeft(mood, 8)+
+			padLeft(formatSentiment(d.sentiment/float64(d.entries)), 11)+
+			padLeft(formatThousands(d.words/d.entries), 8))
+	}
+
+	return strings.Join(sections, "\n")
+}
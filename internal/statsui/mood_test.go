@@ -0,0 +1,56 @@
+package statsui
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCorrelation(t *testing.T) {
+	tests := []struct {
+		name   string
+		xs, ys []float64
+		want   float64 // NaN when there is no correlation to report
+	}{
+		{"perfect positive", []float64{1, 2, 3, 4}, []float64{2, 4, 6, 8}, 1},
+		{"perfect negative", []float64{1, 2, 3, 4}, []float64{8, 6, 4, 2}, -1},
+		{"uncorrelated", []float64{1, 2, 3, 4}, []float64{1, 3, 3, 1}, 0},
+		{"partial", []float64{1, 2, 3}, []float64{1, 3, 2}, 0.5},
+		{"too few points", []float64{1, 2}, []float64{1, 2}, math.NaN()},
+		{"no variation in x", []float64{3, 3, 3}, []float64{1, 2, 3}, math.NaN()},
+		{"no variation in y", []float64{1, 2, 3}, []float64{5, 5, 5}, math.NaN()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := correlation(tt.xs, tt.ys)
+			if math.IsNaN(tt.want) {
+				if !math.IsNaN(got) {
+					t.Errorf("correlation = %v, want NaN", got)
+				}
+				return
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("correlation = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribeCorrelation(t *testing.T) {
+	tests := []struct {
+		r    float64
+		want string
+	}{
+		{math.NaN(), "not enough data"},
+		{0.05, "no clear link (r = +0.05)"},
+		{-0.2, "weak negative (r = -0.20)"},
+		{0.3, "moderate positive (r = +0.30)"},
+		{-0.75, "strong negative (r = -0.75)"},
+	}
+
+	for _, tt := range tests {
+		if got := describeCorrelation(tt.r); got != tt.want {
+			t.Errorf("describeCorrelation(%v) = %q, want %q", tt.r, got, tt.want)
+		}
+	}
+}
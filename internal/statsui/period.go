@@ -0,0 +1,295 @@
+package statsui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// period is the range of dates the dashboard's figures cover.
+type period struct {
+	name   string
+	start  time.Time // Zero for all time
+	end    time.Time
+	yearly bool // Compared with the same dates a year earlier
+}
+
+func allTime() period {
+	return period{name: "All time"}
+}
+
+// periodPresets lists the ranges the range key cycles through. A custom range
+// comes after the last one.
+func periodPresets(now time.Time) []period {
+	today := dateOnly(now)
+	lastDays := func(n int) period {
+		return period{
+			name:  fmt.Sprintf("Last %d days", n),
+			start: today.AddDate(0, 0, -(n - 1)),
+			end:   today,
+		}
+	}
+
+	return []period{
+		allTime(),
+		lastDays(7),
+		lastDays(30),
+		lastDays(90),
+		{
+			name:   "This year",
+			start:  time.Date(today.Year(), time.January, 1, 0, 0, 0, 0, time.UTC),
+			end:    today,
+			yearly: true,
+		},
+	}
+}
+
+// parsePeriod reads a custom range such as "2026-01-01 2026-03-31". The dates
+// may also be separated by "..".
+func parsePeriod(input string) (period, error) {
+	fields := strings.Fields(strings.ReplaceAll(input, "..", " "))
+	if len(fields) != 2 {
+		return period{}, fmt.Errorf("enter a start and end date, e.g. 2026-01-01 2026-03-31")
+	}
+
+	start, err := time.Parse("2006-01-02", fields[0])
+	if err != nil {
+		return period{}, fmt.Errorf("invalid start date %q", fields[0])
+	}
+	end, err := time.Parse("2006-01-02", fields[1])
+	if err != nil {
+		return period{}, fmt.Errorf("invalid end date %q", fields[1])
+	}
+	if end.Before(start) {
+		return period{}, fmt.Errorf("the end date is before the start date")
+	}
+
+	p := period{start: start, end: end}
+	p.name = p.label()
+	return p, nil
+}
+
+func (p period) isAllTime() bool {
+	return p.start.IsZero()
+}
+
+func (p period) contains(date time.Time) bool {
+	if p.isAllTime() {
+		return true
+	}
+	date = dateOnly(date)
+	return !date.Before(p.start) && !date.After(p.end)
+}
+
+// bounds returns the first and last day of p that have happened. All time
+// starts at first.
+func (p period) bounds(first time.Time) (time.Time, time.Time) {
+	today := dateOnly(time.Now())
+	if p.isAllTime() {
+		return dateOnly(first), today
+	}
+	if p.end.After(today) {
+		return p.start, today
+	}
+	return p.start, p.end
+}
+
+// days returns the number of days in p.
+func (p period) days() int {
+	return int(p.end.Sub(p.start).Hours()/24) + 1
+}
+
+// previous returns the period p is compared against: the same dates a year
+// earlier for yearly periods, otherwise the same number of days just before.
+func (p period) previous() period {
+	prev := period{yearly: p.yearly}
+	if p.yearly {
+		prev.start = p.start.AddDate(-1, 0, 0)
+		prev.end = p.end.AddDate(-1, 0, 0)
+	} else {
+		prev.start = p.start.AddDate(0, 0, -p.days())
+		prev.end = p.start.AddDate(0, 0, -1)
+	}
+	prev.name = prev.label()
+	return prev
+}
+
+// label describes the dates in p, e.g. "Sep 19 – Oct 18, 2026".
+func (p period) label() string {
+	if p.isAllTime() {
+		return p.name
+	}
+	if p.start.Year() == p.end.Year() {
+		return p.start.Format("Jan 2") + " – " + p.end.Format("Jan 2, 2006")
+	}
+	return p.start.Format("Jan 2, 2006") + " – " + p.end.Format("Jan 2, 2006")
+}
+
+// key identifies p in cache file names. It is empty for all time.
+func (p period) key() string {
+	if p.isAllTime() {
+		return ""
+	}
+	return p.start.Format("2006-01-02") + "_" + p.end.Format("2006-01-02")
+}
+
+func newRangeInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "2026-01-01 2026-03-31"
+	ti.CharLimit = 32
+	ti.Width = 24
+	return ti
+}
+
+// nextPeriod moves to the next preset, or asks for a custom range after the
+// last one.
+func (m Model) nextPeriod() (Model, tea.Cmd) {
+	presets := periodPresets(time.Now())
+	prevIndex := m.periodIndex
+	m.periodIndex = (m.periodIndex + 1) % (len(presets) + 1)
+
+	if m.periodIndex == len(presets) {
+		m.choosingRange = true
+		m.rangePrevIndex = prevIndex
+		m.rangeErr = nil
+		m.rangeInput.SetValue("")
+		return m, m.rangeInput.Focus()
+	}
+	return m.setPeriod(presets[m.periodIndex])
+}
+
+// setPeriod narrows every tab to p.
+func (m Model) setPeriod(p period) (Model, tea.Cmd) {
+	m.period = p
+	if m.allStats == nil {
+		return m, nil
+	}
+
+	m.stats = m.allStats.within(p)
+	m.dailyCursor = 0
+	for i := range m.viewports {
+		m.viewports[i].GotoTop()
+	}
+	if !p.isAllTime() {
+		_, m.heatmapCursor = p.bounds(p.start)
+		m.selectedYear = m.heatmapCursor.Year()
+	}
+
+	// Insights describe a particular range, so fetch them again
+	m = m.stopInsights()
+	m.insights = ""
+	m.insightsErr = nil
+	if m.activeTab == tabInsights {
+		return m.startInsights(false)
+	}
+	return m, nil
+}
+
+// updateRangeInput handles typing a custom range.
+func (m Model) updateRangeInput(msg tea.Msg) (Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.Type {
+		case tea.KeyEnter:
+			p, err := parsePeriod(m.rangeInput.Value())
+			if err != nil {
+				m.rangeErr = err
+				return m, nil
+			}
+			m.choosingRange = false
+			m.rangeInput.Blur()
+			return m.setPeriod(p)
+		case tea.KeyEsc:
+			// Stay on the range we had
+			m.choosingRange = false
+			m.rangeInput.Blur()
+			m.periodIndex = m.rangePrevIndex
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.rangeInput, cmd = m.rangeInput.Update(msg)
+	return m, cmd
+}
+
+func (m Model) renderRangeInput() string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(subtle)
+
+	parts := []string{
+		lipgloss.NewStyle().Foreground(highlight).Render("Date range: ") + m.rangeInput.View(),
+	}
+	if m.rangeErr != nil {
+		parts = append(parts, lipgloss.NewStyle().Foreground(warning).Render("✗ "+m.rangeErr.Error()))
+	} else {
+		parts = append(parts, labelStyle.Render("enter: apply • esc: cancel"))
+	}
+
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Align(lipgloss.Center).
+		Padding(1, 0, 0, 0).
+		Render(lipgloss.JoinVertical(lipgloss.Center, parts...))
+}
+
+// renderComparison sets the period's figures against the previous period's.
+func (m Model) renderComparison() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(highlight).
+		Bold(true)
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(subtle)
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(highlight).
+		Padding(1, 3)
+
+	if m.period.isAllTime() {
+		return boxStyle.Render(labelStyle.Render("Pick a date range with d to compare it with the one before."))
+	}
+
+	prevPeriod := m.period.previous()
+	cur, prev := m.stats, m.allStats.within(prevPeriod)
+
+	rows := []struct {
+		label       string
+		now, before int
+	}{
+		{"Total words", cur.totalWords, prev.totalWords},
+		{"Active days", cur.totalDays, prev.totalDays},
+		{"Average words/day", int(cur.avgWords + 0.5), int(prev.avgWords + 0.5)},
+		{"Longest streak", cur.longestStreak, prev.longestStreak},
+		{"Streak at the end", cur.currentStreak, prev.currentStreak},
+	}
+
+	lines := []string{
+		titleStyle.Render(m.period.name) + labelStyle.Render(" vs "+prevPeriod.name),
+		"",
+		labelStyle.Render(fmt.Sprintf("%-18s %9s %9s", "", "now", "before")),
+	}
+	for _, row := range rows {
+		lines = append(lines, fmt.Sprintf("%-18s %9s %9s  %s",
+			row.label, formatThousands(row.now), formatThousands(row.before),
+			formatChange(row.now, row.before)))
+	}
+	lines = append(lines, "", labelStyle.Render("c: close"))
+
+	return boxStyle.Render(strings.Join(lines, "\n"))
+}
+
+// updateComparison closes the comparison overlay. It reports whether msg was
+// handled.
+func (m Model) updateComparison(msg tea.KeyMsg) (Model, bool) {
+	if key.Matches(msg, keys.Compare) || msg.Type == tea.KeyEsc {
+		m.comparing = false
+		return m, true
+	}
+	return m, false
+}
@@ -0,0 +1,54 @@
+package statsui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattwhite/river-go/internal/config"
+	"github.com/mattwhite/river-go/internal/streak"
+)
+
+func TestSummarizeStreakInPeriodEndingLater(t *testing.T) {
+	today := dateOnly(time.Now())
+	var notes []noteData
+	for i := 2; i >= 0; i-- {
+		notes = append(notes, noteData{date: today.AddDate(0, 0, -i), words: 100})
+	}
+	goals := config.Goals{}
+	calendar := streak.Compute(streakDays(notes, goals), notes[0].date, time.Now(), streak.Rules{})
+
+	tests := []struct {
+		name    string
+		p       period
+		current int
+	}{
+		{"all time", allTime(), 3},
+		{"ending today", period{start: today.AddDate(0, 0, -6), end: today}, 3},
+		{"ending next month", period{start: today.AddDate(0, 0, -6), end: today.AddDate(0, 1, 0)}, 3},
+		{"ending yesterday", period{start: today.AddDate(0, 0, -6), end: today.AddDate(0, 0, -1)}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := summarize(notes, goals, calendar, tt.p)
+			if s.currentStreak != tt.current || s.longestStreak != tt.current {
+				t.Errorf("current, longest = %d, %d, want %d, %d", s.currentStreak, s.longestStreak, tt.current, tt.current)
+			}
+		})
+	}
+}
+
+func TestParsePeriodEndingLaterKeepsCursorToday(t *testing.T) {
+	today := dateOnly(time.Now())
+	p, err := parsePeriod(today.AddDate(0, 0, -10).Format("2006-01-02") + ".." + today.AddDate(0, 2, 0).Format("2006-01-02"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := InitModel()
+	m.allStats = summarize(nil, config.Goals{}, streak.Calendar{}, allTime())
+	m, _ = m.setPeriod(p)
+	if !m.heatmapCursor.Equal(today) {
+		t.Errorf("heatmap cursor = %s, want today", m.heatmapCursor.Format("2006-01-02"))
+	}
+}
@@ -1,6 +1,8 @@
 package statsui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -11,18 +13,25 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-)
 
-const (
-	dailyGoal = 500
+	"github.com/mattwhite/river-go/internal/achievements"
+	"github.com/mattwhite/river-go/internal/config"
+	"github.com/mattwhite/river-go/internal/editor"
+	"github.com/mattwhite/river-go/internal/language"
+	"github.com/mattwhite/river-go/internal/session"
+	"github.com/mattwhite/river-go/internal/statscache"
+	"github.com/mattwhite/river-go/internal/streak"
 )
 
 var (
 	subtle    = lipgloss.AdaptiveColor{Light: "#D9DCCF", Dark: "#383838"}
 	highlight = lipgloss.AdaptiveColor{Light: "#874BFD", Dark: "#7D56F4"}
 	special   = lipgloss.AdaptiveColor{Light: "#43BF6D", Dark: "#73F59F"}
+	frozen    = lipgloss.AdaptiveColor{Light: "#2B8FD6", Dark: "#6EC1FF"}
 	warning   = lipgloss.AdaptiveColor{Light: "#FF5F87", Dark: "#FF6F91"}
 
 	tabBorderColor  = lipgloss.Color("240")
@@ -54,18 +63,36 @@ const (
 	tabOverview tab = iota
 	tabDaily
 	tabWeekly
+	tabMonthly
+	tabYearly
+	tabHeatmap
 	tabPrompts
+	tabMood
+	tabLanguage
+	tabInsights
 )
 
-var tabNames = []string{"Overview", "Daily", "Weekly", "Prompts"}
+var tabNames = []string{"Overview", "Daily", "Weekly", "Monthly", "Yearly", "Heatmap", "Prompts", "Mood", "Language", "Insights"}
 
 type keyMap struct {
-	Tab   key.Binding
-	Left  key.Binding
-	Right key.Binding
-	Up    key.Binding
-	Down  key.Binding
-	Quit  key.Binding
+	Tab      key.Binding
+	PrevTab  key.Binding
+	Left     key.Binding
+	Right    key.Binding
+	Up       key.Binding
+	Down     key.Binding
+	PageUp   key.Binding
+	PageDown key.Binding
+	Home     key.Binding
+	End      key.Binding
+	Open     key.Binding
+	Preview  key.Binding
+	Range    key.Binding
+	Compare  key.Binding
+	PrevYear key.Binding
+	NextYear key.Binding
+	Refresh  key.Binding
+	Quit     key.Binding
 }
 
 var keys = keyMap{
@@ -73,6 +100,10 @@ var keys = keyMap{
 		key.WithKeys("tab"),
 		key.WithHelp("tab", "next tab"),
 	),
+	PrevTab: key.NewBinding(
+		key.WithKeys("shift+tab"),
+		key.WithHelp("shift+tab", "prev tab"),
+	),
 	Left: key.NewBinding(
 		key.WithKeys("left", "h"),
 		key.WithHelp("←/h", "prev tab"),
@@ -89,6 +120,50 @@ var keys = keyMap{
 		key.WithKeys("down", "j"),
 		key.WithHelp("↓/j", "scroll down"),
 	),
+	PageUp: key.NewBinding(
+		key.WithKeys("pgup", "b"),
+		key.WithHelp("pgup/b", "page up"),
+	),
+	PageDown: key.NewBinding(
+		key.WithKeys("pgdown", " ", "f"),
+		key.WithHelp("pgdn/f", "page down"),
+	),
+	Home: key.NewBinding(
+		key.WithKeys("home", "g"),
+		key.WithHelp("home/g", "top"),
+	),
+	End: key.NewBinding(
+		key.WithKeys("end", "G"),
+		key.WithHelp("end/G", "bottom"),
+	),
+	Open: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "open"),
+	),
+	Preview: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "preview"),
+	),
+	Range: key.NewBinding(
+		key.WithKeys("d"),
+		key.WithHelp("d", "date range"),
+	),
+	Compare: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "compare"),
+	),
+	PrevYear: key.NewBinding(
+		key.WithKeys("["),
+		key.WithHelp("[", "prev year"),
+	),
+	NextYear: key.NewBinding(
+		key.WithKeys("]"),
+		key.WithHelp("]", "next year"),
+	),
+	Refresh: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "regenerate"),
+	),
 	Quit: key.NewBinding(
 		key.WithKeys("q", "ctrl+c", "esc"),
 		key.WithHelp("q", "quit"),
@@ -96,33 +171,80 @@ var keys = keyMap{
 }
 
 type Model struct {
-	width      int
-	height     int
-	activeTab  tab
-	loading    bool
-	error      error
-	stats      *stats
-	spinner    spinner.Model
-	progress   progress.Model
-	scrollY    int
-	maxScrollY int
+	width         int
+	height        int
+	activeTab     tab
+	loading       bool
+	error         error
+	stats         *stats // Figures for the chosen period
+	allStats      *stats // Figures for all time, which stats is narrowed from
+	spinner       spinner.Model
+	progress      progress.Model
+	viewports     []viewport.Model // One per tab, so each keeps its scroll position
+	heatmapCursor time.Time
+	selectedYear  int
+	dailyCursor   int // Days back from the end of the period
+
+	period         period
+	periodIndex    int // Position in periodPresets; one past the end is custom
+	choosingRange  bool
+	rangePrevIndex int // periodIndex before the range picker opened, restored on esc
+	rangeInput     textinput.Model
+	rangeErr       error
+	comparing      bool
+
+	editing     bool
+	editor      editor.Model
+	previewing  bool
+	preview     viewport.Model
+	previewDate time.Time
+	previewText string // The previewed entry as read when the preview opened
+
+	insights        string
+	insightsErr     error
+	insightsLoading bool
+	insightsCancel  context.CancelFunc // Stops the analysis being fetched
 }
 
 type stats struct {
-	notes         []noteData
-	totalWords    int
-	totalDays     int
-	currentStreak int
-	longestStreak int
-	avgWords      float64
-	todayWords    int
-	weeklyData    []weekData
-	monthlyData   []monthData
+	notes           []noteData
+	byDate          map[string]noteData // notes keyed by 2006-01-02
+	totalWords      int
+	totalDays       int
+	currentStreak   int
+	longestStreak   int
+	avgWords        float64
+	todayWords      int
+	todayTypingTime time.Duration
+	goals           config.Goals
+	calendar        streak.Calendar
+	period          period // The dates these figures cover
+	weeklyData      []weekData
+	monthlyData     []monthData
+	yearlyData      []yearData
+	totalTypingTime time.Duration
+	wordsPerMinute  float64
+	timeOfDay       []timeOfDayData
+	achievements    []achievements.Status
+	bestDay         achievements.Record
+	unlocked        map[string]string // Achievement ID -> date it was stored
 }
 
 type noteData struct {
-	date  time.Time
-	words int
+	date       time.Time
+	words      int
+	typingTime time.Duration
+	sessions   []session.Session
+	prompt     string
+	mood       int     // 1-5, 0 when not rated
+	sentiment  float64 // -1 to 1
+	language   language.Metrics
+}
+
+type timeOfDayData struct {
+	name       string
+	words      int
+	typingTime time.Duration
 }
 
 type weekData struct {
@@ -151,11 +273,21 @@ func InitModel() Model {
 		progress.WithoutPercentage(),
 	)
 
+	viewports := make([]viewport.Model, len(tabNames))
+	for i := range viewports {
+		viewports[i] = viewport.New(0, 0)
+	}
+
 	return Model{
-		loading:   true,
-		spinner:   s,
-		progress:  p,
-		activeTab: tabOverview,
+		loading:       true,
+		spinner:       s,
+		progress:      p,
+		viewports:     viewports,
+		activeTab:     tabOverview,
+		heatmapCursor: dateOnly(time.Now()),
+		selectedYear:  time.Now().Year(),
+		period:        allTime(),
+		rangeInput:    newRangeInput(),
 	}
 }
 
@@ -173,48 +305,165 @@ type statsMsg struct {
 
 func loadStats() tea.Msg {
 	stats, err := collectStats()
+	if err == nil {
+		recordAchievements(stats)
+	}
 	return statsMsg{stats: stats, err: err}
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m, cmd := m.update(msg)
+	if m.changesContent(msg) {
+		m.syncViewport()
+	}
+	return m, cmd
+}
+
+// changesContent reports whether msg may have changed what the active tab
+// shows. Rendering a tab can mean walking every note, so cursor blinks and
+// spinner ticks elsewhere don't trigger it.
+func (m Model) changesContent(msg tea.Msg) bool {
+	switch msg.(type) {
+	case tea.WindowSizeMsg, tea.KeyMsg, statsMsg, insightsMsg, editor.ClosedMsg:
+		return true
+	case spinner.TickMsg:
+		return m.insightsLoading && m.activeTab == tabInsights && !m.previewing
+	}
+	return false
+}
+
+func (m Model) update(msg tea.Msg) (Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
+	// An open editor or preview takes over input; background work carries on
+	switch msg.(type) {
+	case tea.WindowSizeMsg, statsMsg, insightsMsg, spinner.TickMsg:
+	case tea.KeyMsg, tea.MouseMsg:
+		if m.editing {
+			return m.updateEditor(msg)
+		}
+		if m.choosingRange {
+			return m.updateRangeInput(msg)
+		}
+		if m.previewing {
+			return m.updatePreview(msg)
+		}
+	default:
+		if m.editing {
+			return m.updateEditor(msg)
+		}
+		if m.choosingRange {
+			return m.updateRangeInput(msg)
+		}
+	}
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		m.progress.Width = min(msg.Width-20, 60)
+		m.progress.Width = min(msg.Width-20, 58)
+		if m.editing {
+			return m.updateEditor(msg)
+		}
 
 	case statsMsg:
 		m.loading = false
 		if msg.err != nil {
 			m.error = msg.err
 		} else {
-			m.stats = msg.stats
+			m.allStats = msg.stats
+			m.stats = msg.stats.within(m.period)
 		}
 
 	case tea.KeyMsg:
+		if m.comparing {
+			if updated, handled := m.updateComparison(msg); handled {
+				return updated, nil
+			}
+		}
+
+		// The heatmap uses the arrow keys to move its cursor
+		if m.activeTab == tabHeatmap && m.stats != nil {
+			if updated, handled := m.updateHeatmap(msg); handled {
+				return updated, nil
+			}
+		}
+
+		// The daily list moves a cursor instead of scrolling
+		if m.activeTab == tabDaily && m.stats != nil {
+			if updated, cmd, handled := m.updateDaily(msg); handled {
+				return updated, cmd
+			}
+		}
+
+		vp := &m.viewports[m.activeTab]
+
 		switch {
 		case key.Matches(msg, keys.Quit):
-			return m, tea.Quit
+			return m.stopInsights(), tea.Quit
 		case key.Matches(msg, keys.Tab), key.Matches(msg, keys.Right):
 			m.activeTab = tab((int(m.activeTab) + 1) % len(tabNames))
-			m.scrollY = 0
-		case key.Matches(msg, keys.Left):
+			return m.enterTab()
+		case key.Matches(msg, keys.PrevTab), key.Matches(msg, keys.Left):
 			m.activeTab = tab((int(m.activeTab) + len(tabNames) - 1) % len(tabNames))
-			m.scrollY = 0
+			return m.enterTab()
 		case key.Matches(msg, keys.Down):
-			if m.scrollY < m.maxScrollY {
-				m.scrollY++
-			}
+			vp.ScrollDown(1)
 		case key.Matches(msg, keys.Up):
-			if m.scrollY > 0 {
-				m.scrollY--
+			vp.ScrollUp(1)
+		case key.Matches(msg, keys.PageDown):
+			vp.PageDown()
+		case key.Matches(msg, keys.PageUp):
+			vp.PageUp()
+		case key.Matches(msg, keys.Home):
+			vp.GotoTop()
+		case key.Matches(msg, keys.End):
+			vp.GotoBottom()
+		case key.Matches(msg, keys.Range):
+			if m.stats != nil {
+				return m.nextPeriod()
+			}
+		case key.Matches(msg, keys.Compare):
+			m.comparing = m.stats != nil
+		case key.Matches(msg, keys.Refresh):
+			if m.activeTab == tabInsights {
+				return m.startInsights(true)
 			}
+		case key.Matches(msg, keys.PrevYear):
+			if first, _ := m.yearRange(); m.stats != nil && m.selectedYear > first {
+				m.selectedYear--
+			}
+		case key.Matches(msg, keys.NextYear):
+			if _, last := m.yearRange(); m.stats != nil && m.selectedYear < last {
+				m.selectedYear++
+			}
+		}
+
+	case tea.MouseMsg:
+		// The viewport handles the mouse wheel
+		var cmd tea.Cmd
+		m.viewports[m.activeTab], cmd = m.viewports[m.activeTab].Update(msg)
+		cmds = append(cmds, cmd)
+
+	case insightsMsg:
+		m.insightsLoading = false
+		m.insightsCancel = nil
+
+		// The range changed or the tab was left while this was running; ask
+		// again if the tab is showing
+		if msg.period.key() != m.period.key() || errors.Is(msg.err, context.Canceled) {
+			if m.activeTab == tabInsights {
+				return m.startInsights(false)
+			}
+			return m, nil
 		}
 
+		m.insights = msg.text
+		m.insightsErr = msg.err
+		m.viewports[tabInsights].GotoTop()
+
 	case spinner.TickMsg:
-		if m.loading {
+		if m.loading || m.insightsLoading {
 			var cmd tea.Cmd
 			m.spinner, cmd = m.spinner.Update(msg)
 			cmds = append(cmds, cmd)
@@ -224,7 +473,43 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// syncViewport renders the active tab into its viewport, so scrolling knows
+// how tall the content is.
+func (m *Model) syncViewport() {
+	if m.stats == nil || m.width == 0 || m.editing {
+		return
+	}
+
+	if m.previewing {
+		m.preview.Width = max(1, m.width-4)
+		m.preview.Height = m.contentHeight()
+		m.preview.SetContent(m.renderPreview())
+		return
+	}
+
+	vp := &m.viewports[m.activeTab]
+	vp.Width = max(1, m.width-4)
+	vp.Height = m.contentHeight()
+	vp.SetContent(m.renderContent())
+}
+
+// enterTab runs any work a tab needs the first time it is shown. Leaving the
+// Insights tab stops its analysis.
+func (m Model) enterTab() (Model, tea.Cmd) {
+	if m.activeTab != tabInsights {
+		m = m.stopInsights()
+	}
+	if m.activeTab == tabInsights && m.insights == "" && m.insightsErr == nil {
+		return m.startInsights(false)
+	}
+	return m, nil
+}
+
 func (m Model) View() string {
+	if m.editing {
+		return m.editor.View()
+	}
+
 	if m.loading {
 		return m.renderLoading()
 	}
@@ -277,13 +562,15 @@ func (m Model) renderError() string {
 func (m Model) renderStats() string {
 	header := m.renderHeader()
 	tabs := m.renderTabs()
-	content := m.renderContent()
+	content := m.activeViewport().View()
+	if m.comparing {
+		content = lipgloss.Place(m.width-4, m.contentHeight(),
+			lipgloss.Center, lipgloss.Center, m.renderComparison())
+	}
 	footer := m.renderFooter()
 
-	availHeight := m.height - lipgloss.Height(header) - lipgloss.Height(tabs) - lipgloss.Height(footer) - 2
-
 	contentBox := lipgloss.NewStyle().
-		Height(availHeight).
+		Height(m.contentHeight()).
 		Width(m.width).
 		Padding(0, 2).
 		Render(content)
@@ -297,6 +584,20 @@ func (m Model) renderStats() string {
 	)
 }
 
+// activeViewport returns the viewport on screen: the preview when one is open,
+// otherwise the active tab's.
+func (m Model) activeViewport() viewport.Model {
+	if m.previewing {
+		return m.preview
+	}
+	return m.viewports[m.activeTab]
+}
+
+// contentHeight returns the number of lines available to the active tab.
+func (m Model) contentHeight() int {
+	return max(1, m.height-lipgloss.Height(m.renderHeader())-lipgloss.Height(m.renderTabs())-lipgloss.Height(m.renderFooter())-2)
+}
+
 func (m Model) renderHeader() string {
 	streak := ""
 	if m.stats.currentStreak > 0 {
@@ -307,6 +608,9 @@ func (m Model) renderHeader() string {
 		Foreground(subtle).
 		Render(fmt.Sprintf("%s words • %d days%s",
 			formatNumber(m.stats.totalWords), m.stats.totalDays, streak))
+	if !m.period.isAllTime() {
+		header += lipgloss.NewStyle().Foreground(highlight).Render(" • " + m.period.name)
+	}
 
 	return lipgloss.NewStyle().
 		Width(m.width).
@@ -316,20 +620,28 @@ func (m Model) renderHeader() string {
 }
 
 func (m Model) renderTabs() string {
-	var tabs []string
+	var row string
 
-	for i, name := range tabNames {
-		if tab(i) == m.activeTab {
-			tabs = append(tabs, activeTab(name))
-		} else {
-			tabs = append(tabs, inactiveTab(name))
+	// Narrow the tabs until they fit the window
+	for padding := 2; padding >= 0; padding-- {
+		var tabs []string
+
+		for i, name := range tabNames {
+			if tab(i) == m.activeTab {
+				tabs = append(tabs, activeTab(name, padding))
+			} else {
+				tabs = append(tabs, inactiveTab(name, padding))
+			}
 		}
-	}
 
-	row := lipgloss.JoinHorizontal(
-		lipgloss.Top,
-		tabs...,
-	)
+		row = lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			tabs...,
+		)
+		if lipgloss.Width(row) <= m.width {
+			break
+		}
+	}
 
 	gap := lipgloss.NewStyle().
 		Width(m.width - lipgloss.Width(row)).
@@ -340,21 +652,21 @@ func (m Model) renderTabs() string {
 		Render(lipgloss.JoinHorizontal(lipgloss.Bottom, row, gap))
 }
 
-func activeTab(name string) string {
+func activeTab(name string, padding int) string {
 	return lipgloss.NewStyle().
 		Border(activeTabBorder).
 		BorderForeground(highlight).
 		Foreground(highlight).
-		Padding(0, 2).
+		Padding(0, padding).
 		Render(name)
 }
 
-func inactiveTab(name string) string {
+func inactiveTab(name string, padding int) string {
 	return lipgloss.NewStyle().
 		Border(tabBorder).
 		BorderForeground(tabBorderColor).
 		Foreground(subtle).
-		Padding(0, 2).
+		Padding(0, padding).
 		Render(name)
 }
 
@@ -366,8 +678,20 @@ func (m Model) renderContent() string {
 		return m.renderDaily()
 	case tabWeekly:
 		return m.renderWeekly()
+	case tabMonthly:
+		return m.renderMonthly()
+	case tabYearly:
+		return m.renderYearly()
+	case tabHeatmap:
+		return m.renderHeatmap()
 	case tabPrompts:
 		return m.renderPrompts()
+	case tabMood:
+		return m.renderMood()
+	case tabLanguage:
+		return m.renderLanguage()
+	case tabInsights:
+		return m.renderInsights()
 	default:
 		return ""
 	}
@@ -388,23 +712,49 @@ func (m Model) renderOverview() string {
 	stats := m.renderQuickStats()
 	sections = append(sections, "", stats)
 
+	// Time spent writing
+	writingTime := m.renderWritingTime()
+	sections = append(sections, "", writingTime)
+
+	// Milestones
+	sections = append(sections, "", m.renderAchievements())
+
 	return lipgloss.JoinVertical(lipgloss.Left, sections...)
 }
 
 func (m Model) renderTodayProgress() string {
-	progress := float64(m.stats.todayWords) / float64(dailyGoal)
+	today := time.Now()
+	wordGoal := m.stats.goals.WordsFor(today)
+	timeGoal := m.stats.goals.MinutesFor(today)
+
+	// The bar follows the word goal, or the time goal when there is none
+	progress := 0.0
+	if wordGoal > 0 {
+		progress = float64(m.stats.todayWords) / float64(wordGoal)
+	} else if timeGoal > 0 {
+		progress = float64(m.stats.todayTypingTime) / float64(timeGoal)
+	}
 
 	progressBar := m.progress.ViewAs(progress)
 
 	status := ""
-	if progress >= 1.0 {
+	if m.stats.goals.Met(today, m.stats.todayWords, m.stats.todayTypingTime) {
 		status = " ✓"
 	}
 
+	goalText := fmt.Sprintf("%d words", m.stats.todayWords)
+	if wordGoal > 0 {
+		goalText = fmt.Sprintf("%d / %d words", m.stats.todayWords, wordGoal)
+	}
+	if timeGoal > 0 {
+		goalText += fmt.Sprintf(" • %d / %d min",
+			int(m.stats.todayTypingTime.Minutes()), int(timeGoal.Minutes()))
+	}
+
 	header := lipgloss.NewStyle().
 		Foreground(highlight).
 		Bold(true).
-		Render(fmt.Sprintf("Today: %d / %d words%s", m.stats.todayWords, dailyGoal, status))
+		Render(fmt.Sprintf("Today: %s%s", goalText, status))
 
 	return lipgloss.NewStyle().
 		Padding(0, 1).
@@ -437,6 +787,52 @@ func (m Model) renderQuickStats() string {
 		Render(strings.Join(stats, "  •  "))
 }
 
+func (m Model) renderWritingTime() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(highlight).
+		Bold(true)
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(subtle)
+
+	valueStyle := lipgloss.NewStyle().
+		Foreground(highlight)
+
+	title := titleStyle.Render("Writing Time")
+
+	if m.stats.totalTypingTime == 0 {
+		return lipgloss.NewStyle().
+			Padding(0, 1).
+			Render(lipgloss.JoinVertical(
+				lipgloss.Left,
+				title,
+				labelStyle.Render("Time is tracked from your next session in the editor."),
+			))
+	}
+
+	stats := []string{
+		fmt.Sprintf("%s %s",
+			valueStyle.Render(formatDuration(m.stats.totalTypingTime)),
+			labelStyle.Render("written")),
+		fmt.Sprintf("%s %s",
+			valueStyle.Render(fmt.Sprintf("%.0f", m.stats.wordsPerMinute)),
+			labelStyle.Render("words/min")),
+	}
+	if best := bestTimeOfDay(m.stats.timeOfDay); best != nil {
+		stats = append(stats, fmt.Sprintf("%s %s",
+			labelStyle.Render("best in the"),
+			valueStyle.Render(strings.ToLower(best.name))))
+	}
+
+	return lipgloss.NewStyle().
+		Padding(0, 1).
+		Render(lipgloss.JoinVertical(
+			lipgloss.Left,
+			title,
+			strings.Join(stats, "  •  "),
+		))
+}
+
 func (m Model) renderRecentActivity() string {
 	titleStyle := lipgloss.NewStyle().
 		Foreground(highlight).
@@ -449,27 +845,26 @@ func (m Model) renderRecentActivity() string {
 	for i := 6; i >= 0; i-- {
 		date := time.Now().AddDate(0, 0, -i)
 		words := m.getWordsForDate(date)
+		goal := m.stats.goals.WordsFor(date)
 
 		dayStyle := lipgloss.NewStyle()
 		bar := ""
 		wordStr := ""
 
 		if words == -1 {
-			// Missing day
-			dayStyle = dayStyle.Foreground(warning)
-			bar = strings.Repeat("─", 12)
-			wordStr = "miss"
-		} else if words >= dailyGoal {
+			// Missing, rest or frozen day
+			dayStyle, bar, wordStr = m.renderEmptyDay(date, 12)
+		} else if m.goalMet(date) {
 			dayStyle = dayStyle.Foreground(special)
-			bar = m.renderSparkBar(words, dailyGoal, 12)
+			bar = m.renderSparkBar(words, goal, 12)
 			wordStr = fmt.Sprintf("%4d", words)
 		} else if words > 0 {
 			dayStyle = dayStyle.Foreground(lipgloss.Color("252"))
-			bar = m.renderSparkBar(words, dailyGoal, 12)
+			bar = m.renderSparkBar(words, goal, 12)
 			wordStr = fmt.Sprintf("%4d", words)
 		} else {
 			dayStyle = dayStyle.Foreground(subtle)
-			bar = m.renderSparkBar(words, dailyGoal, 12)
+			bar = m.renderSparkBar(words, goal, 12)
 			wordStr = fmt.Sprintf("%4d", words)
 		}
 
@@ -496,25 +891,10 @@ func (m Model) renderDaily() string {
 	// Create a scrollable list of all days including missing ones
 	days := []string{}
 
-	// Get date range
-	var startDate, endDate time.Time
-	if len(m.stats.notes) > 0 {
-		startDate = m.stats.notes[0].date
-		endDate = time.Now()
-	} else {
-		// No notes, show last 30 days
-		endDate = time.Now()
-		startDate = endDate.AddDate(0, 0, -30)
-	}
-
-	// Create map for quick lookup
-	noteMap := make(map[string]int)
-	for _, note := range m.stats.notes {
-		noteMap[note.date.Format("2006-01-02")] = note.words
-	}
+	noteMap := m.stats.byDate
 
 	// Iterate from most recent to oldest
-	for date := endDate; !date.Before(startDate); date = date.AddDate(0, 0, -1) {
+	for i, date := range m.dailyDates() {
 		dateKey := date.Format("2006-01-02")
 		dateStr := date.Format("Jan 2")
 		if date.Weekday() == time.Monday {
@@ -525,40 +905,53 @@ func (m Model) renderDaily() string {
 		bar := ""
 		wordStr := ""
 
-		if words, exists := noteMap[dateKey]; exists {
+		note, exists := noteMap[dateKey]
+		if exists {
 			// Day with note
-			if words >= dailyGoal {
+			met := m.stats.goals.Met(date, note.words, note.typingTime)
+			if met {
 				lineStyle = lineStyle.Foreground(special)
-			} else if words > 0 {
+			} else if note.words > 0 {
 				lineStyle = lineStyle.Foreground(lipgloss.Color("252"))
 			} else {
 				lineStyle = lineStyle.Foreground(subtle)
 			}
-			bar = m.renderSparkBar(words, dailyGoal, 15)
-			wordStr = fmt.Sprintf("%4d", words)
+			bar = m.renderSparkBar(note.words, m.stats.goals.WordsFor(date), 15)
+			wordStr = fmt.Sprintf("%4d", note.words)
+			if met {
+				wordStr += " ✓"
+			}
 		} else {
-			// Missing day
-			lineStyle = lineStyle.Foreground(warning)
-			bar = strings.Repeat("─", 15)
-			wordStr = "miss"
+			// Missing, rest or frozen day
+			lineStyle, bar, wordStr = m.renderEmptyDay(date, 15)
+		}
+
+		cursor := "  "
+		if i == m.dailyCursor {
+			cursor = "▸ "
+			lineStyle = lineStyle.Bold(true)
+			if !date.Equal(dateOnly(time.Now())) && (!exists || note.words == 0) {
+				wordStr += lipgloss.NewStyle().Foreground(subtle).Render("  ↵ backfill this day")
+			}
 		}
 
 		line := fmt.Sprintf("%-12s %s %s", dateStr, bar, wordStr)
-		days = append(days, lineStyle.Render(line))
+		days = append(days, cursor+lineStyle.Render(line))
 	}
 
-	// Apply scrolling
-	visibleHeight := m.height - 8
-	startIdx := m.scrollY
-	endIdx := min(startIdx+visibleHeight, len(days))
+	return strings.Join(days, "\n")
+}
 
-	if startIdx < len(days) {
-		days = days[startIdx:endIdx]
+// renderEmptyDay returns the style, bar and label for a day without an entry.
+func (m Model) renderEmptyDay(date time.Time, width int) (lipgloss.Style, string, string) {
+	switch m.stats.calendar.Status(date) {
+	case streak.Rest:
+		return lipgloss.NewStyle().Foreground(subtle), strings.Repeat("·", width), "rest"
+	case streak.Frozen:
+		return lipgloss.NewStyle().Foreground(frozen), strings.Repeat("┄", width), "❄ frozen"
+	default:
+		return lipgloss.NewStyle().Foreground(warning), strings.Repeat("─", width), "miss"
 	}
-
-	m.maxScrollY = max(0, len(days)-visibleHeight)
-
-	return strings.Join(days, "\n")
 }
 
 func (m Model) renderWeekly() string {
@@ -567,24 +960,16 @@ func (m Model) renderWeekly() string {
 		week := m.stats.weeklyData[i]
 		weekStr := week.startDate.Format("Jan 2")
 
-		// Calculate expected days (7 unless it's the current week)
-		expectedDays := 7
-		if week.startDate.AddDate(0, 0, 7).After(time.Now()) {
-			// Current week - count days from start to today
-			expectedDays = int(time.Since(week.startDate).Hours()/24) + 1
-			if expectedDays > 7 {
-				expectedDays = 7
-			}
-		}
-
+		expectedDays := weekExpectedDays(week.startDate)
 		missingDays := expectedDays - week.days
-		bar := m.renderSparkBar(week.words, expectedDays*dailyGoal, 15)
+		goal := weekGoal(m.stats.goals, week.startDate, expectedDays)
+		bar := m.renderSparkBar(week.words, goal, 15)
 
 		lineStyle := lipgloss.NewStyle()
 		if missingDays > 0 && missingDays < expectedDays {
 			// Some missing days
 			lineStyle = lineStyle.Foreground(lipgloss.Color("214")) // Orange
-		} else if week.avg >= float64(dailyGoal) {
+		} else if goal > 0 && week.words >= goal {
 			lineStyle = lineStyle.Foreground(special)
 		} else if week.words > 0 {
 			lineStyle = lineStyle.Foreground(lipgloss.Color("252"))
@@ -593,6 +978,9 @@ func (m Model) renderWeekly() string {
 		}
 
 		dayInfo := fmt.Sprintf("%d/%d days", week.days, expectedDays)
+		if goal > 0 && week.words >= goal {
+			dayInfo += " ✓"
+		}
 		line := fmt.Sprintf("Week %-7s %s %5d total • %s",
 			weekStr, bar, week.words, dayInfo)
 		weeks = append(weeks, lineStyle.Render(line))
@@ -601,53 +989,44 @@ func (m Model) renderWeekly() string {
 	return strings.Join(weeks, "\n")
 }
 
-func (m Model) renderTrendsOld() string {
-	titleStyle := lipgloss.NewStyle().
-		Foreground(highlight).
-		Bold(true).
-		MarginBottom(1)
-
-	sections := []string{}
-
-	// Monthly trends
-	monthTitle := titleStyle.Render("Monthly Trends")
-	months := []string{}
-
-	for _, month := range m.stats.monthlyData {
-		monthStr := fmt.Sprintf("%s %d", month.month.String()[:3], month.year)
-
-		bar := m.renderSparkBar(month.words, month.days*dailyGoal, 20)
-
-		lineStyle := lipgloss.NewStyle()
-		if month.avg >= float64(dailyGoal) {
-			lineStyle = lineStyle.Foreground(special)
-		} else {
-			lineStyle = lineStyle.Foreground(lipgloss.Color("252"))
+// weekExpectedDays returns how many days of the week starting at weekStart
+// have passed: 7 unless it's the current week.
+func weekExpectedDays(weekStart time.Time) int {
+	expectedDays := 7
+	if weekStart.AddDate(0, 0, 7).After(time.Now()) {
+		// Current week - count days from start to today
+		expectedDays = int(time.Since(weekStart).Hours()/24) + 1
+		if expectedDays > 7 {
+			expectedDays = 7
 		}
-
-		line := fmt.Sprintf("%-10s %s %6d words • %.0f/day",
-			monthStr, bar, month.words, month.avg)
-		months = append(months, lineStyle.Render(line))
 	}
+	return expectedDays
+}
 
-	sections = append(sections,
-		lipgloss.JoinVertical(lipgloss.Left, monthTitle, strings.Join(months, "\n")))
-
-	// Writing patterns
-	patternTitle := titleStyle.Render("Writing Patterns")
-	patterns := m.analyzePatterns()
-
-	patternStyle := lipgloss.NewStyle().
-		Foreground(subtle).
-		MarginLeft(2)
-
-	sections = append(sections, "",
-		lipgloss.JoinVertical(lipgloss.Left,
-			patternTitle,
-			patternStyle.Render(patterns),
-		))
+// weekGoal returns the word goal for a week, counting only the first
+// expectedDays days unless an explicit weekly goal is configured.
+func weekGoal(goals config.Goals, weekStart time.Time, expectedDays int) int {
+	if goals.Weekly > 0 || expectedDays >= 7 {
+		return goals.WeeklyWords(weekStart)
+	}
+	goal := 0
+	for i := 0; i < expectedDays; i++ {
+		goal += goals.WordsFor(weekStart.AddDate(0, 0, i))
+	}
+	return goal
+}
 
-	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+// rangeGoal returns the word goal for the days from start to end, summing
+// each day's goal with its weekday override. Days after today don't count yet.
+func rangeGoal(goals config.Goals, start, end time.Time) int {
+	if today := dateOnly(time.Now()); end.After(today) {
+		end = today
+	}
+	goal := 0
+	for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+		goal += goals.WordsFor(date)
+	}
+	return goal
 }
 
 func (m Model) renderSparkBar(value, max, width int) string {
@@ -683,16 +1062,41 @@ func (m Model) renderSparkBar(value, max, width int) string {
 }
 
 func (m Model) renderFooter() string {
+	if m.choosingRange {
+		return m.renderRangeInput()
+	}
+
 	help := []string{}
 
-	if m.activeTab == tabDaily {
-		help = append(help, "↑↓: scroll")
+	switch {
+	case m.previewing:
+		help = append(help, "↑↓: scroll", "enter: edit", "esc: close")
+	case m.activeTab == tabDaily:
+		open := "enter: open"
+		if day := m.dailySelection(); !day.Equal(dateOnly(time.Now())) && !m.hasNote(day) {
+			open = "enter: backfill"
+		}
+		help = append(help, "↑↓: select", open, "p: preview", "←→: tabs")
+	case m.activeTab == tabOverview, m.activeTab == tabWeekly, m.activeTab == tabPrompts, m.activeTab == tabMood, m.activeTab == tabLanguage:
+		help = append(help, "↑↓: scroll", "←→: tabs")
+	case m.activeTab == tabMonthly, m.activeTab == tabYearly:
+		help = append(help, "↑↓: scroll", "[ ]: year", "←→: tabs")
+	case m.activeTab == tabInsights:
+		help = append(help, "↑↓: scroll", "r: regenerate", "←→: tabs")
+	case m.activeTab == tabHeatmap:
+		help = append(help, "←↑↓→: move", "[ ]: year", "tab: tabs")
+	default:
+		help = append(help, "←→: tabs")
+	}
+
+	if !m.previewing {
+		help = append(help, "d: range", "c: compare", "q: quit")
 	}
 
-	help = append(help,
-		"←→: tabs",
-		"q: quit",
-	)
+	// Show where we are when the tab is taller than the screen
+	if vp := m.activeViewport(); vp.TotalLineCount() > vp.Height && vp.Height > 0 {
+		help = append([]string{fmt.Sprintf("↕ %d%%", int(vp.ScrollPercent()*100)), "pgup/pgdn: page"}, help...)
+	}
 
 	return lipgloss.NewStyle().
 		Foreground(subtle).
@@ -703,14 +1107,10 @@ func (m Model) renderFooter() string {
 }
 
 func (m Model) getWordsForDate(date time.Time) int {
-	dateStr := date.Format("2006-01-02")
-
 	// Check if this date is in the past and should have a note
 	if date.Before(time.Now().AddDate(0, 0, 1)) {
-		for _, note := range m.stats.notes {
-			if note.date.Format("2006-01-02") == dateStr {
-				return note.words
-			}
+		if note, ok := m.noteFor(date); ok {
+			return note.words
 		}
 		// Date is in the past but has no note - it's missing
 		if len(m.stats.notes) > 0 && !date.Before(m.stats.notes[0].date) {
@@ -720,6 +1120,12 @@ func (m Model) getWordsForDate(date time.Time) int {
 	return 0
 }
 
+// goalMet reports whether the entry for date met the configured goals.
+func (m Model) goalMet(date time.Time) bool {
+	note, ok := m.noteFor(date)
+	return ok && m.stats.goals.Met(date, note.words, note.typingTime)
+}
+
 func (m Model) analyzePatterns() string {
 	if len(m.stats.notes) == 0 {
 		return "No data yet"
@@ -747,14 +1153,22 @@ func (m Model) analyzePatterns() string {
 		}
 	}
 
-	// Find best time (simplified - just morning/afternoon/evening based on file creation)
 	consistency := float64(m.stats.currentStreak) / float64(m.stats.totalDays) * 100
 
 	patterns := []string{
 		fmt.Sprintf("📅 Best day: %s (%.0f words avg)", bestDay, bestAvg),
+	}
+
+	// Find best time of day from the editor's session logs
+	if best := bestTimeOfDay(m.stats.timeOfDay); best != nil {
+		patterns = append(patterns, fmt.Sprintf("🕐 Best time: %s (%.0f words/min)",
+			strings.ToLower(best.name), wordsPerMinute(best.words, best.typingTime)))
+	}
+
+	patterns = append(patterns,
 		fmt.Sprintf("🔥 Longest streak: %d days", m.stats.longestStreak),
 		fmt.Sprintf("📊 Consistency: %.1f%%", consistency),
-	}
+	)
 
 	return strings.Join(patterns, "\n")
 }
@@ -883,48 +1297,44 @@ func (m Model) renderPrompts() string {
 }
 
 func collectStats() (*stats, error) {
-	homeDir, err := os.UserHomeDir()
+	// The cache only re-reads notes that changed since the last launch
+	entries, err := statscache.Load()
 	if err != nil {
 		return nil, err
 	}
 
-	riverDir := filepath.Join(homeDir, "river", "notes")
-	files, err := filepath.Glob(filepath.Join(riverDir, "*.md"))
+	// Session logs are optional; without them typing time is simply zero
+	sessions, err := session.LoadAll()
 	if err != nil {
-		return nil, err
+		sessions = map[string][]session.Session{}
 	}
 
 	notes := []noteData{}
 	dateMap := make(map[string]int)
 
-	for _, file := range files {
-		base := filepath.Base(file)
-		if strings.HasPrefix(base, ".") {
-			continue
-		}
-
-		dateStr := strings.TrimSuffix(base, ".md")
-		date, err := time.Parse("2006-01-02", dateStr)
+	for _, entry := range entries {
+		date, err := time.Parse("2006-01-02", entry.Date)
 		if err != nil {
 			continue
 		}
 
-		content, err := os.ReadFile(file)
-		if err != nil {
-			continue
+		var typingTime time.Duration
+		for _, s := range sessions[entry.Date] {
+			typingTime += s.ActiveTime()
 		}
 
-		// Count words (excluding HTML comments for ghost text)
-		text := string(content)
-		text = removeHTMLComments(text)
-		words := len(strings.Fields(text))
-
 		notes = append(notes, noteData{
-			date:  date,
-			words: words,
+			date:       date,
+			words:      entry.Words,
+			typingTime: typingTime,
+			sessions:   sessions[entry.Date],
+			prompt:     entry.Prompt,
+			mood:       entry.Mood,
+			sentiment:  entry.Sentiment,
+			language:   entry.Language,
 		})
 
-		dateMap[dateStr] = words
+		dateMap[entry.Date] = entry.Words
 	}
 
 	// Sort notes by date
@@ -932,105 +1342,106 @@ func collectStats() (*stats, error) {
 		return notes[i].date.Before(notes[j].date)
 	})
 
-	// Calculate statistics
-	stats := &stats{
-		notes:     notes,
-		totalDays: len(notes),
-	}
+	goals := config.LoadGoals()
+	rules := streak.LoadRules()
 
+	var calendar streak.Calendar
 	if len(notes) > 0 {
-		// Calculate totals
-		for _, note := range notes {
-			stats.totalWords += note.words
-		}
-
-		// Today's words
-		today := time.Now().Format("2006-01-02")
-		if words, exists := dateMap[today]; exists {
-			stats.todayWords = words
-		}
-
-		// Average
-		stats.avgWords = float64(stats.totalWords) / float64(stats.totalDays)
-
-		// Streaks
-		stats.currentStreak = calculateCurrentStreak(dateMap)
-		stats.longestStreak = calculateLongestStreak(notes)
+		calendar = streak.Compute(streakDays(notes, goals),
+			notes[0].date, time.Now(), rules)
+	}
 
-		// Weekly data
-		stats.weeklyData = calculateWeeklyData(notes)
+	stats := summarize(notes, goals, calendar, allTime())
+	evaluateAchievements(stats, rules)
 
-		// Monthly data
-		stats.monthlyData = calculateMonthlyData(notes)
+	// Today's words
+	today := time.Now().Format("2006-01-02")
+	if words, exists := dateMap[today]; exists {
+		stats.todayWords = words
+	}
+	for _, s := range sessions[today] {
+		stats.todayTypingTime += s.ActiveTime()
 	}
 
 	return stats, nil
 }
 
-func removeHTMLComments(text string) string {
-	for {
-		start := strings.Index(text, "<!--")
-		if start == -1 {
-			break
-		}
-		end := strings.Index(text[start:], "-->")
-		if end == -1 {
-			break
+// within returns the stats for the notes written during p. Today's progress
+// and the streak calendar are kept as they are.
+func (s *stats) within(p period) *stats {
+	notes := []noteData{}
+	for _, note := range s.notes {
+		if p.contains(note.date) {
+			notes = append(notes, note)
 		}
-		text = text[:start] + text[start+end+3:]
 	}
-	return text
-}
 
-func calculateCurrentStreak(dateMap map[string]int) int {
-	streak := 0
-	date := time.Now()
+	filtered := summarize(notes, s.goals, s.calendar, p)
+	filtered.todayWords = s.todayWords
+	filtered.todayTypingTime = s.todayTypingTime
+	return filtered
+}
 
-	// Check if we have today or yesterday
-	todayKey := date.Format("2006-01-02")
-	yesterdayKey := date.AddDate(0, 0, -1).Format("2006-01-02")
+// summarize calculates the figures for notes, which all fall within p.
+func summarize(notes []noteData, goals config.Goals, calendar streak.Calendar, p period) *stats {
+	stats := &stats{
+		notes:     notes,
+		byDate:    make(map[string]noteData, len(notes)),
+		totalDays: len(notes),
+		goals:     goals,
+		calendar:  calendar,
+		period:    p,
+	}
+	for _, note := range notes {
+		stats.byDate[note.date.Format("2006-01-02")] = note
+	}
 
-	if _, todayExists := dateMap[todayKey]; !todayExists {
-		if _, yesterdayExists := dateMap[yesterdayKey]; !yesterdayExists {
-			return 0
-		}
-		date = date.AddDate(0, 0, -1)
+	if len(notes) == 0 {
+		return stats
 	}
 
-	// Count backwards - streak breaks on missing days
-	for {
-		key := date.Format("2006-01-02")
-		if _, exists := dateMap[key]; !exists {
-			break
-		}
-		streak++
-		date = date.AddDate(0, 0, -1)
+	// Calculate totals
+	for _, note := range notes {
+		stats.totalWords += note.words
 	}
 
-	return streak
-}
+	// Average
+	stats.avgWords = float64(stats.totalWords) / float64(stats.totalDays)
 
-func calculateLongestStreak(notes []noteData) int {
-	if len(notes) == 0 {
-		return 0
-	}
+	// Streaks, counting only the days inside the period
+	start, end := p.bounds(notes[0].date)
+	stats.currentStreak, stats.longestStreak = calendar.Between(start, end)
 
-	longest := 1
-	current := 1
+	// Weekly data
+	stats.weeklyData = calculateWeeklyData(notes)
 
-	for i := 1; i < len(notes); i++ {
-		diff := notes[i].date.Sub(notes[i-1].date).Hours() / 24
-		if diff == 1 {
-			current++
-			if current > longest {
-				longest = current
-			}
-		} else {
-			current = 1
+	// Monthly and yearly data
+	stats.monthlyData = calculateMonthlyData(notes)
+	stats.yearlyData = calculateYearlyData(notes)
+
+	// Writing time
+	wordsAdded := 0
+	for _, note := range notes {
+		stats.totalTypingTime += note.typingTime
+		for _, s := range note.sessions {
+			wordsAdded += s.WordsAdded
 		}
 	}
+	stats.wordsPerMinute = wordsPerMinute(wordsAdded, stats.totalTypingTime)
+	stats.timeOfDay = calculateTimeOfDay(notes)
+
+	return stats
+}
 
-	return longest
+// streakDays returns the dates (2006-01-02) whose entries extend a streak.
+func streakDays(notes []noteData, goals config.Goals) map[string]bool {
+	days := make(map[string]bool)
+	for _, note := range notes {
+		if goals.CountsForStreak(note.date, note.words, note.typingTime) {
+			days[note.date.Format("2006-01-02")] = true
+		}
+	}
+	return days
 }
 
 func calculateWeeklyData(notes []noteData) []weekData {
@@ -1069,11 +1480,6 @@ func calculateWeeklyData(notes []noteData) []weekData {
 		return weeks[i].startDate.Before(weeks[j].startDate)
 	})
 
-	// Keep last 8 weeks
-	if len(weeks) > 8 {
-		weeks = weeks[len(weeks)-8:]
-	}
-
 	return weeks
 }
 
@@ -1111,12 +1517,71 @@ func calculateMonthlyData(notes []noteData) []monthData {
 		return months[i].month < months[j].month
 	})
 
-	// Keep last 6 months
-	if len(months) > 6 {
-		months = months[len(months)-6:]
+	return months
+}
+
+// calculateTimeOfDay buckets session activity by the hour each session started.
+func calculateTimeOfDay(notes []noteData) []timeOfDayData {
+	buckets := []timeOfDayData{
+		{name: "Morning"},
+		{name: "Afternoon"},
+		{name: "Evening"},
+		{name: "Night"},
 	}
 
-	return months
+	for _, note := range notes {
+		for _, s := range note.sessions {
+			var i int
+			switch hour := s.Start.Hour(); {
+			case hour >= 5 && hour < 12:
+				i = 0
+			case hour >= 12 && hour < 17:
+				i = 1
+			case hour >= 17 && hour < 22:
+				i = 2
+			default:
+				i = 3
+			}
+			buckets[i].words += s.WordsAdded
+			buckets[i].typingTime += s.ActiveTime()
+		}
+	}
+
+	return buckets
+}
+
+// bestTimeOfDay returns the bucket with the highest words per minute, ignoring
+// buckets with too little writing time to be meaningful.
+func bestTimeOfDay(buckets []timeOfDayData) *timeOfDayData {
+	var best *timeOfDayData
+	bestRate := 0.0
+	for i := range buckets {
+		if buckets[i].typingTime < 5*time.Minute {
+			continue
+		}
+		rate := wordsPerMinute(buckets[i].words, buckets[i].typingTime)
+		if best == nil || rate > bestRate {
+			best = &buckets[i]
+			bestRate = rate
+		}
+	}
+	return best
+}
+
+func wordsPerMinute(words int, d time.Duration) float64 {
+	if d < time.Minute {
+		return 0
+	}
+	return float64(words) / d.Minutes()
+}
+
+func formatDuration(d time.Duration) string {
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	if hours > 0 {
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	}
+	return fmt.Sprintf("%dm", minutes)
 }
 
 func formatNumber(n int) string {
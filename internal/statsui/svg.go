@@ -0,0 +1,341 @@
+package statsui
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/mattwhite/river-go/internal/streak"
+)
+
+// SVGCharts are the charts that can be exported as images.
+var SVGCharts = []string{"heatmap", "weekly", "monthly"}
+
+// SVG chart sizes, in pixels
+const (
+	svgCell      = 11 // Heatmap cell
+	svgCellStep  = 14 // Heatmap cell plus gap
+	svgWeeks     = 26 // Bars on the weekly chart
+	svgMonths    = 12 // Bars on the monthly chart
+	svgBarWidth  = 720
+	svgBarHeight = 240
+)
+
+const svgFont = `-apple-system, BlinkMacSystemFont, 'Segoe UI', Helvetica, Arial, sans-serif`
+
+// svgTheme holds the colors for one background. They come from the same
+// adaptive colors the dashboard uses, so exports look like the terminal.
+type svgTheme struct {
+	background string
+	text       string
+	muted      string
+	track      string
+	bar        string
+	goal       string
+	frozen     string
+	levels     []string
+}
+
+func newSVGTheme(name string) (svgTheme, error) {
+	pick := func(c lipgloss.AdaptiveColor) string { return c.Light }
+	t := svgTheme{background: "#FFFFFF", text: "#24292F", muted: "#57606A"}
+	switch name {
+	case "light", "":
+	case "dark":
+		pick = func(c lipgloss.AdaptiveColor) string { return c.Dark }
+		t = svgTheme{background: "#0D1117", text: "#E6EDF3", muted: "#7D8590"}
+	default:
+		return t, fmt.Errorf("unknown theme %q, expected light or dark", name)
+	}
+
+	t.bar = pick(highlight)
+	t.goal = pick(special)
+	t.frozen = pick(frozen)
+	for _, level := range heatmapLevels {
+		t.levels = append(t.levels, pick(level))
+	}
+	t.track = t.levels[0]
+	return t, nil
+}
+
+// svgWriter builds an SVG document.
+type svgWriter struct {
+	b     strings.Builder
+	theme svgTheme
+}
+
+func (s *svgWriter) start(width, height int) {
+	fmt.Fprintf(&s.b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="%s">`+"\n",
+		width, height, width, height, svgFont)
+	fmt.Fprintf(&s.b, `<rect width="%d" height="%d" rx="6" fill="%s"/>`+"\n", width, height, s.theme.background)
+}
+
+func (s *svgWriter) end() {
+	s.b.WriteString("</svg>\n")
+}
+
+// text writes a label. anchor is start, middle or end.
+func (s *svgWriter) text(x, y int, size int, color, anchor, weight, content string) {
+	fmt.Fprintf(&s.b, `<text x="%d" y="%d" font-size="%d" fill="%s" text-anchor="%s" font-weight="%s">%s</text>`+"\n",
+		x, y, size, color, anchor, weight, html.EscapeString(content))
+}
+
+// rect writes a box with a tooltip.
+func (s *svgWriter) rect(x, y, width, height int, color, tip string) {
+	fmt.Fprintf(&s.b, `<rect x="%d" y="%d" width="%d" height="%d" rx="2" fill="%s"><title>%s</title></rect>`+"\n",
+		x, y, width, height, color, html.EscapeString(tip))
+}
+
+func (s *svgWriter) line(x1, y1, x2, y2 int, color string, dashed bool) {
+	dash := ""
+	if dashed {
+		dash = ` stroke-dasharray="3 3"`
+	}
+	fmt.Fprintf(&s.b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="1"%s/>`+"\n",
+		x1, y1, x2, y2, color, dash)
+}
+
+// WriteSVG draws chart ("heatmap", "weekly" or "monthly") as an SVG image in
+// the light or dark theme. year picks the heatmap's year; zero means this
+// year.
+func WriteSVG(w io.Writer, chart, theme string, year int) error {
+	t, err := newSVGTheme(theme)
+	if err != nil {
+		return err
+	}
+
+	s, err := collectStats()
+	if err != nil {
+		return err
+	}
+
+	out := &svgWriter{theme: t}
+	switch chart {
+	case "heatmap":
+		if year == 0 {
+			year = time.Now().Year()
+		}
+		drawHeatmapSVG(out, s, year)
+	case "weekly":
+		drawBarsSVG(out, "Words per week", weeklyBars(s))
+	case "monthly":
+		drawBarsSVG(out, "Words per month", monthlyBars(s))
+	default:
+		return fmt.Errorf("unknown chart %q, expected one of %s", chart, strings.Join(SVGCharts, ", "))
+	}
+
+	_, err = io.WriteString(w, out.b.String())
+	return err
+}
+
+// drawHeatmapSVG draws the year like the Heatmap tab: a column per week,
+// Sunday at the top.
+func drawHeatmapSVG(out *svgWriter, s *stats, year int) {
+	t := out.theme
+	today := dateOnly(time.Now())
+
+	noteMap := make(map[string]noteData)
+	yearWords, yearDays := 0, 0
+	for _, note := range s.notes {
+		noteMap[note.date.Format("2006-01-02")] = note
+		if note.date.Year() == year {
+			yearWords += note.words
+			yearDays++
+		}
+	}
+
+	jan1 := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	gridStart := jan1.AddDate(0, 0, -int(jan1.Weekday()))
+	dec31 := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	weeks := int(dec31.Sub(gridStart).Hours()/24)/7 + 1
+
+	left, top := 36, 56
+	width := left + weeks*svgCellStep + 16
+	height := top + 7*svgCellStep + 40
+	out.start(width, height)
+
+	out.text(16, 26, 14, t.text, "start", "600", fmt.Sprintf("%d", year))
+	out.text(56, 26, 12, t.muted, "start", "normal",
+		fmt.Sprintf("%s words in %d days", formatThousands(yearWords), yearDays))
+
+	for month := time.January; month <= time.December; month++ {
+		first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+		col := int(first.Sub(gridStart).Hours()/24) / 7
+		out.text(left+col*svgCellStep, top-8, 10, t.muted, "start", "normal", first.Format("Jan"))
+	}
+	for weekday, label := range []string{"", "Mon", "", "Wed", "", "Fri", ""} {
+		if label != "" {
+			out.text(left-6, top+weekday*svgCellStep+svgCell-1, 10, t.muted, "end", "normal", label)
+		}
+	}
+
+	for week := 0; week < weeks; week++ {
+		for weekday := 0; weekday < 7; weekday++ {
+			date := gridStart.AddDate(0, 0, week*7+weekday)
+			if date.Year() != year || date.After(today) {
+				continue
+			}
+
+			color, tip := t.levels[0], date.Format("Mon, Jan 2, 2006")+": no entry"
+			if note, ok := noteMap[date.Format("2006-01-02")]; ok {
+				color = t.levels[heatmapLevel(note.words, s.goals.WordsFor(date))]
+				tip = fmt.Sprintf("%s: %s words", date.Format("Mon, Jan 2, 2006"), formatThousands(note.words))
+			} else if s.calendar.Status(date) == streak.Frozen {
+				color, tip = t.frozen, date.Format("Mon, Jan 2, 2006")+": streak freeze"
+			}
+			out.rect(left+week*svgCellStep, top+weekday*svgCellStep, svgCell, svgCell, color, tip)
+		}
+	}
+
+	// Legend, aligned to the right edge of the grid
+	legendY := top + 7*svgCellStep + 14
+	x := left + weeks*svgCellStep - len(t.levels)*svgCellStep - 34
+	out.text(x-6, legendY+svgCell-1, 10, t.muted, "end", "normal", "Less")
+	for i, color := range t.levels {
+		out.rect(x+i*svgCellStep, legendY, svgCell, svgCell, color, "")
+	}
+	out.text(x+len(t.levels)*svgCellStep+2, legendY+svgCell-1, 10, t.muted, "start", "normal", "More")
+	out.text(left, legendY+svgCell-1, 10, t.muted, "start", "normal", "Relative to the daily goal")
+
+	out.end()
+}
+
+// svgBar is one bar on a bar chart.
+type svgBar struct {
+	label string // Shown under the bar; empty for none
+	tip   string
+	words int
+	goal  int // Zero for none
+}
+
+// weeklyBars returns the last svgWeeks weeks, including ones with no writing.
+func weeklyBars(s *stats) []svgBar {
+	byWeek := make(map[string]weekData)
+	for _, week := range s.weeklyData {
+		byWeek[week.startDate.Format("2006-01-02")] = week
+	}
+
+	thisWeek := dateOnly(time.Now())
+	thisWeek = thisWeek.AddDate(0, 0, -int(thisWeek.Weekday()))
+
+	bars := make([]svgBar, 0, svgWeeks)
+	for i := svgWeeks - 1; i >= 0; i-- {
+		start := thisWeek.AddDate(0, 0, -7*i)
+		week := byWeek[start.Format("2006-01-02")]
+		bar := svgBar{
+			tip:   fmt.Sprintf("Week of %s: %s words in %d days", start.Format("Jan 2, 2006"), formatThousands(week.words), week.days),
+			words: week.words,
+			goal:  weekGoal(s.goals, start, weekExpectedDays(start)),
+		}
+		if i%4 == 0 {
+			bar.label = start.Format("Jan 2")
+		}
+		bars = append(bars, bar)
+	}
+	return bars
+}
+
+// monthlyBars returns the last svgMonths months, including ones with no
+// writing.
+func monthlyBars(s *stats) []svgBar {
+	byMonth := make(map[string]monthData)
+	for _, month := range s.monthlyData {
+		byMonth[fmt.Sprintf("%d-%02d", month.year, month.month)] = month
+	}
+
+	now := time.Now()
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	bars := make([]svgBar, 0, svgMonths)
+	for i := svgMonths - 1; i >= 0; i-- {
+		start := thisMonth.AddDate(0, -i, 0)
+		month := byMonth[start.Format("2006-01")]
+		label := start.Format("Jan")
+		if start.Month() == time.January || i == svgMonths-1 {
+			label = start.Format("Jan 06")
+		}
+		bars = append(bars, svgBar{
+			label: label,
+			tip:   fmt.Sprintf("%s: %s words in %d days", start.Format("January 2006"), formatThousands(month.words), month.days),
+			words: month.words,
+			goal:  rangeGoal(s.goals, start, start.AddDate(0, 1, -1)),
+		})
+	}
+	return bars
+}
+
+// drawBarsSVG draws a bar chart. Bars that reach their goal use the goal
+// color, and each goal is marked with a dashed line.
+func drawBarsSVG(out *svgWriter, title string, bars []svgBar) {
+	t := out.theme
+	left, right, top, bottom := 56, 16, 48, 32
+	plotWidth := svgBarWidth - left - right
+	plotHeight := svgBarHeight - top - bottom
+	baseline := top + plotHeight
+
+	total, peak := 0, 0
+	for _, bar := range bars {
+		total += bar.words
+		peak = max(peak, max(bar.words, bar.goal))
+	}
+	peak = niceCeiling(peak)
+
+	out.start(svgBarWidth, svgBarHeight)
+	out.text(16, 26, 14, t.text, "start", "600", title)
+	out.text(svgBarWidth-right, 26, 12, t.muted, "end", "normal", formatThousands(total)+" words")
+
+	// Gridlines at zero, half and the top
+	for _, value := range []int{0, peak / 2, peak} {
+		y := baseline - value*plotHeight/peak
+		out.line(left, y, left+plotWidth, y, t.track, value != 0)
+		out.text(left-8, y+4, 10, t.muted, "end", "normal", formatNumber(value))
+	}
+
+	slot := plotWidth / len(bars)
+	barWidth := max(2, slot*3/4)
+	for i, bar := range bars {
+		x := left + i*slot + (slot-barWidth)/2
+
+		color := t.bar
+		if bar.goal > 0 && bar.words >= bar.goal {
+			color = t.goal
+		}
+		if height := bar.words * plotHeight / peak; height > 0 {
+			out.rect(x, baseline-height, barWidth, height, color, bar.tip)
+		} else {
+			// Something to hover over, even for an empty period
+			out.rect(x, baseline-1, barWidth, 1, t.track, bar.tip)
+		}
+
+		if bar.goal > 0 {
+			y := baseline - bar.goal*plotHeight/peak
+			out.line(x-1, y, x+barWidth+1, y, t.goal, true)
+		}
+		if bar.label != "" {
+			out.text(x+barWidth/2, baseline+18, 10, t.muted, "middle", "normal", bar.label)
+		}
+	}
+
+	out.end()
+}
+
+// niceCeiling rounds n up to a round number for the top of an axis.
+func niceCeiling(n int) int {
+	if n <= 0 {
+		return 100
+	}
+	step := 1
+	for step*10 < n {
+		step *= 10
+	}
+	for _, m := range []int{1, 2, 5, 10} {
+		if step*m >= n {
+			return step * m
+		}
+	}
+	return step * 10
+}
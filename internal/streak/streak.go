@@ -0,0 +1,326 @@
+package streak
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattwhite/river-go/internal/config"
+	"github.com/mattwhite/river-go/internal/statscache"
+)
+
+// Status describes how a single day affects a streak.
+type Status int
+
+const (
+	// Missing days break a streak.
+	Missing Status = iota
+	// Written days extend a streak.
+	Written
+	// Rest days are planned days off; they neither extend nor break a streak.
+	Rest
+	// Frozen days are missed days covered by a streak freeze.
+	Frozen
+)
+
+// Rules configure which missed days are forgiven.
+//
+// They are configured in ~/river/.config:
+//
+//	REST_DAYS=saturday,sunday  weekdays that never break a streak
+//	STREAK_FREEZES=2           freezes available each month
+//	AUTO_FREEZE=false          only use freezes marked with 'river rest'
+type Rules struct {
+	RestDays        map[time.Weekday]bool
+	FreezesPerMonth int
+	AutoFreeze      bool
+	Marked          map[string]bool // Dates marked with 'river rest'
+}
+
+// Calendar is the streak status of every day from the first entry to today.
+type Calendar struct {
+	days    map[string]Status
+	runs    map[string]int
+	Current int
+	Longest int
+}
+
+// LoadRules reads the streak settings from the config file and the days
+// marked with 'river rest'.
+func LoadRules() Rules {
+	v := config.Load()
+
+	rules := Rules{
+		RestDays:        make(map[time.Weekday]bool),
+		FreezesPerMonth: v.Int("STREAK_FREEZES", 0),
+		AutoFreeze:      v.Bool("AUTO_FREEZE", true),
+		Marked:          make(map[string]bool),
+	}
+
+	for _, name := range strings.Split(v.String("REST_DAYS", ""), ",") {
+		if day, ok := parseWeekday(name); ok {
+			rules.RestDays[day] = true
+		}
+	}
+
+	marked, _ := loadMarked()
+	for _, dateStr := range marked {
+		rules.Marked[dateStr] = true
+	}
+
+	return rules
+}
+
+func parseWeekday(name string) (time.Weekday, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if len(name) < 3 {
+		return 0, false
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.HasPrefix(strings.ToLower(day.String()), name) {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+func restFile() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, "river", "notes", ".rest"), nil
+}
+
+func loadMarked() ([]string, error) {
+	filename, err := restFile()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var dates []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if _, err := time.Parse("2006-01-02", line); err == nil {
+			dates = append(dates, line)
+		}
+	}
+	return dates, nil
+}
+
+// MarkRest spends a streak freeze on date. It returns the number of freezes
+// used in that month, including this one. Days in the future and days that
+// already have writing can't be marked.
+func MarkRest(date time.Time) (int, error) {
+	rules := LoadRules()
+	dateStr := date.Format("2006-01-02")
+	if rules.Marked[dateStr] {
+		return 0, fmt.Errorf("%s is already marked as a rest day", dateStr)
+	}
+	if truncate(date).After(truncate(time.Now())) {
+		return 0, fmt.Errorf("%s hasn't happened yet; mark it as a rest day once it has", dateStr)
+	}
+	if hasWriting(dateStr) {
+		return 0, fmt.Errorf("%s already has an entry, so it doesn't need a freeze", dateStr)
+	}
+
+	used := 1
+	for marked := range rules.Marked {
+		if strings.HasPrefix(marked, date.Format("2006-01")) {
+			used++
+		}
+	}
+	if used > rules.FreezesPerMonth {
+		return 0, fmt.Errorf("no streak freezes left for %s (STREAK_FREEZES=%d)",
+			date.Format("January 2006"), rules.FreezesPerMonth)
+	}
+
+	marked, err := loadMarked()
+	if err != nil {
+		return 0, err
+	}
+	marked = append(marked, dateStr)
+	sort.Strings(marked)
+
+	filename, err := restFile()
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return 0, err
+	}
+	if err := os.WriteFile(filename, []byte(strings.Join(marked, "\n")+"\n"), 0644); err != nil {
+		return 0, err
+	}
+
+	return used, nil
+}
+
+// hasWriting reports whether the note for dateStr has any words outside its
+// comment header.
+func hasWriting(dateStr string) bool {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return false
+	}
+	data, err := os.ReadFile(filepath.Join(homeDir, "river", "notes", dateStr+".md"))
+	if err != nil {
+		return false
+	}
+	return len(strings.Fields(statscache.RemoveHTMLComments(string(data)))) > 0
+}
+
+// Compute classifies every day from first to today. written holds the dates
+// (2006-01-02) whose entries count towards a streak.
+func Compute(written map[string]bool, first, today time.Time, rules Rules) Calendar {
+	cal := Calendar{days: make(map[string]Status), runs: make(map[string]int)}
+
+	first = truncate(first)
+	today = truncate(today)
+	if first.After(today) {
+		return cal
+	}
+
+	// Marked freezes come out of each month's allowance before automatic ones
+	used := make(map[string]int)
+	for dateStr := range rules.Marked {
+		used[dateStr[:7]]++
+	}
+
+	autoFrozen := make(map[string]bool)
+	current := 0
+	for date := first; !date.After(today); date = date.AddDate(0, 0, 1) {
+		dateStr := date.Format("2006-01-02")
+
+		switch {
+		case written[dateStr]:
+			cal.days[dateStr] = Written
+		case rules.Marked[dateStr]:
+			cal.days[dateStr] = Frozen
+		case rules.RestDays[date.Weekday()]:
+			cal.days[dateStr] = Rest
+		case date.Equal(today):
+			// Today can still be written; it doesn't break anything yet
+			cal.days[dateStr] = Missing
+		case autoFrozen[dateStr]:
+			cal.days[dateStr] = Frozen
+		case rules.AutoFreeze && current > 0 && freezeGap(missedRun(date, today, written, rules), used, rules, autoFrozen):
+			cal.days[dateStr] = Frozen
+		default:
+			cal.days[dateStr] = Missing
+		}
+
+		switch cal.days[dateStr] {
+		case Written:
+			current++
+		case Missing:
+			if !date.Equal(today) {
+				current = 0
+			}
+		}
+		if current > cal.Longest {
+			cal.Longest = current
+		}
+		cal.runs[dateStr] = current
+	}
+	cal.Current = current
+
+	return cal
+}
+
+// missedRun returns the missed days from start up to a written day or today,
+// skipping rest days and marked freezes, which cost nothing.
+func missedRun(start, today time.Time, written map[string]bool, rules Rules) []time.Time {
+	var run []time.Time
+	for date := start; date.Before(today); date = date.AddDate(0, 0, 1) {
+		dateStr := date.Format("2006-01-02")
+		if written[dateStr] {
+			break
+		}
+		if rules.Marked[dateStr] || rules.RestDays[date.Weekday()] {
+			continue
+		}
+		run = append(run, date)
+	}
+	return run
+}
+
+// freezeGap spends freezes on every day of run when each month's remaining
+// allowance covers it, recording them in frozen. A gap too long to cover
+// breaks the streak anyway, so it spends nothing and the freezes stay
+// available for later gaps.
+func freezeGap(run []time.Time, used map[string]int, rules Rules, frozen map[string]bool) bool {
+	need := make(map[string]int)
+	for _, date := range run {
+		need[date.Format("2006-01")]++
+	}
+	for month, n := range need {
+		if used[month]+n > rules.FreezesPerMonth {
+			return false
+		}
+	}
+
+	for month, n := range need {
+		used[month] += n
+	}
+	for _, date := range run {
+		frozen[date.Format("2006-01-02")] = true
+	}
+	return true
+}
+
+// Status returns the status of date. Days outside the calendar are Missing.
+func (c Calendar) Status(date time.Time) Status {
+	return c.days[date.Format("2006-01-02")]
+}
+
+// StreakOn returns the length of the streak as of the end of date.
+func (c Calendar) StreakOn(date time.Time) int {
+	return c.runs[date.Format("2006-01-02")]
+}
+
+// Between returns the streak as of end and the longest streak, counting only
+// days from start to end. A streak already running at start counts from there.
+func (c Calendar) Between(start, end time.Time) (current, longest int) {
+	for date := truncate(start); !date.After(truncate(end)); date = date.AddDate(0, 0, 1) {
+		switch {
+		case c.StreakOn(date) == 0:
+			current = 0
+		case c.Status(date) == Written:
+			current++
+		}
+		if current > longest {
+			longest = current
+		}
+	}
+	return current, longest
+}
+
+// String returns a lower-case name for the status.
+func (s Status) String() string {
+	switch s {
+	case Written:
+		return "written"
+	case Rest:
+		return "rest"
+	case Frozen:
+		return "frozen"
+	default:
+		return "missing"
+	}
+}
+
+func truncate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
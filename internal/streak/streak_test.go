@@ -0,0 +1,181 @@
+package streak
+
+import (
+	"testing"
+	"time"
+)
+
+// march returns a date in March 2026, which starts on a Sunday.
+func march(day int) time.Time {
+	return time.Date(2026, 3, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestCompute(t *testing.T) {
+	tests := []struct {
+		name     string
+		written  []int // Days of March 2026
+		today    time.Time
+		rules    Rules
+		current  int
+		longest  int
+		statuses map[int]Status // Days of March 2026 worth checking
+	}{
+		{
+			name:    "every day written",
+			written: []int{1, 2, 3, 4},
+			today:   march(4),
+			current: 4,
+			longest: 4,
+		},
+		{
+			name:     "missed day breaks the streak",
+			written:  []int{1, 2, 3, 5, 6},
+			today:    march(6),
+			current:  2,
+			longest:  3,
+			statuses: map[int]Status{4: Missing},
+		},
+		{
+			name:     "today can still be written",
+			written:  []int{1, 2, 3},
+			today:    march(4),
+			current:  3,
+			longest:  3,
+			statuses: map[int]Status{4: Missing},
+		},
+		{
+			name:     "rest day neither extends nor breaks",
+			written:  []int{2, 3, 4, 5, 6, 8},
+			today:    march(8),
+			rules:    Rules{RestDays: map[time.Weekday]bool{time.Saturday: true}},
+			current:  6,
+			longest:  6,
+			statuses: map[int]Status{7: Rest, 8: Written},
+		},
+		{
+			name:     "writing on a rest day counts",
+			written:  []int{6, 7, 8},
+			today:    march(8),
+			rules:    Rules{RestDays: map[time.Weekday]bool{time.Saturday: true}},
+			current:  3,
+			longest:  3,
+			statuses: map[int]Status{7: Written},
+		},
+		{
+			name:     "marked day is frozen",
+			written:  []int{1, 2, 3, 5},
+			today:    march(5),
+			rules:    Rules{Marked: map[string]bool{"2026-03-04": true}},
+			current:  4,
+			longest:  4,
+			statuses: map[int]Status{4: Frozen},
+		},
+		{
+			name:     "gap within the allowance is frozen",
+			written:  []int{1, 2, 3, 6},
+			today:    march(6),
+			rules:    Rules{FreezesPerMonth: 2, AutoFreeze: true},
+			current:  4,
+			longest:  4,
+			statuses: map[int]Status{4: Frozen, 5: Frozen},
+		},
+		{
+			name:     "gap longer than the allowance spends nothing",
+			written:  []int{1, 2, 3, 7, 8, 10},
+			today:    march(10),
+			rules:    Rules{FreezesPerMonth: 2, AutoFreeze: true},
+			current:  3,
+			longest:  3,
+			statuses: map[int]Status{4: Missing, 5: Missing, 6: Missing, 9: Frozen},
+		},
+		{
+			name:     "rest days inside a gap cost nothing",
+			written:  []int{5, 9},
+			today:    march(9),
+			rules:    Rules{RestDays: map[time.Weekday]bool{time.Saturday: true, time.Sunday: true}, FreezesPerMonth: 1, AutoFreeze: true},
+			current:  2,
+			longest:  2,
+			statuses: map[int]Status{6: Frozen, 7: Rest, 8: Rest},
+		},
+		{
+			name:     "marked days use up the allowance",
+			written:  []int{1, 2, 3, 5, 7},
+			today:    march(7),
+			rules:    Rules{FreezesPerMonth: 1, AutoFreeze: true, Marked: map[string]bool{"2026-03-04": true}},
+			current:  1,
+			longest:  4,
+			statuses: map[int]Status{4: Frozen, 6: Missing},
+		},
+		{
+			name:     "freezes stay unused without auto-freeze",
+			written:  []int{1, 2, 4},
+			today:    march(4),
+			rules:    Rules{FreezesPerMonth: 2},
+			current:  1,
+			longest:  2,
+			statuses: map[int]Status{3: Missing},
+		},
+		{
+			name:     "gap before today is frozen once it's over",
+			written:  []int{1, 2},
+			today:    march(4),
+			rules:    Rules{FreezesPerMonth: 1, AutoFreeze: true},
+			current:  2,
+			longest:  2,
+			statuses: map[int]Status{3: Frozen, 4: Missing},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			written := make(map[string]bool)
+			for _, day := range tt.written {
+				written[march(day).Format("2006-01-02")] = true
+			}
+
+			cal := Compute(written, march(tt.written[0]), tt.today, tt.rules)
+			if cal.Current != tt.current || cal.Longest != tt.longest {
+				t.Errorf("current, longest = %d, %d, want %d, %d", cal.Current, cal.Longest, tt.current, tt.longest)
+			}
+			for day, want := range tt.statuses {
+				if got := cal.Status(march(day)); got != want {
+					t.Errorf("March %d is %s, want %s", day, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestComputeFreezesEachMonth(t *testing.T) {
+	written := map[string]bool{
+		"2026-03-29": true,
+		"2026-03-30": true,
+		"2026-04-02": true,
+	}
+	rules := Rules{FreezesPerMonth: 1, AutoFreeze: true}
+
+	cal := Compute(written, march(29), time.Date(2026, 4, 2, 0, 0, 0, 0, time.UTC), rules)
+	if cal.Current != 3 {
+		t.Errorf("current = %d, want 3", cal.Current)
+	}
+	for _, date := range []time.Time{march(31), time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)} {
+		if got := cal.Status(date); got != Frozen {
+			t.Errorf("%s is %s, want frozen", date.Format("2006-01-02"), got)
+		}
+	}
+}
+
+func TestCalendarStreakOn(t *testing.T) {
+	written := map[string]bool{
+		"2026-03-01": true,
+		"2026-03-02": true,
+		"2026-03-04": true,
+	}
+	cal := Compute(written, march(1), march(5), Rules{})
+
+	for day, want := range map[int]int{1: 1, 2: 2, 3: 0, 4: 1, 5: 1, 6: 0} {
+		if got := cal.StreakOn(march(day)); got != want {
+			t.Errorf("StreakOn(March %d) = %d, want %d", day, got, want)
+		}
+	}
+}